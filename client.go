@@ -3,6 +3,7 @@ package dbleases
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -23,10 +24,19 @@ type DB interface {
 }
 
 type Repository interface {
-	InsertLease(ctx context.Context, clientID string, leaseName string, value int, ttl time.Duration, status lease.Status) error
+	InsertLease(ctx context.Context, info lease.Info, ttl time.Duration) error
 	GetAndRefreshLeases(ctx context.Context, names []string, clientID string, ttl time.Duration) ([]lease.Info, error)
+	GetLeases(ctx context.Context, names []string) ([]lease.Info, error)
 	SetLeaseStatus(ctx context.Context, clientID string, leaseName string, value int, status lease.Status) error
 	DeleteLeases(ctx context.Context, clientID string) error
+	InsertLeaseDefinition(ctx context.Context, leaseName string, size int, strategy string, fixedSize bool) (lease.Definition, error)
+	GetLeaseDefinitions(ctx context.Context, names []string) ([]lease.Definition, error)
+	UpdateLeaseSize(ctx context.Context, leaseName string, size int, quiet time.Duration) (lease.Definition, error)
+	InsertResources(ctx context.Context, leaseName string, keys []string) error
+	DeleteResources(ctx context.Context, leaseName string, keys []string) error
+	GetResources(ctx context.Context, names []string) ([]lease.Resource, error)
+	AcquirePermit(ctx context.Context, leaseName string, clientID string, ttl time.Duration, permits int) (lease.Permit, bool, error)
+	ReleasePermit(ctx context.Context, leaseName string, clientID string, value int) error
 }
 
 func NewClient(db DB, clientID string, options ...Option) (*Client, error) {
@@ -46,14 +56,23 @@ func NewClient(db DB, clientID string, options ...Option) (*Client, error) {
 		return nil, err
 	}
 
+	return newClient(db, clientID, o, repo), nil
+}
+
+// newClient with validated options and the repository of the options.
+func newClient(db DB, clientID string, o Options, repo Repository) *Client {
 	return &Client{
 		ID:                clientID,
 		db:                db,
 		opt:               o,
 		repo:              repo,
 		heartbeatStopChan: make(chan struct{}),
+		closing:           make(chan struct{}),
 		leases:            make(map[string]*Lease),
-	}, nil
+		resources:         make(map[string]*ResourceLease),
+		permits:           make(map[string]*permit),
+		elections:         make(map[string]*Leadership),
+	}
 }
 
 type Client struct {
@@ -63,6 +82,9 @@ type Client struct {
 	repo Repository
 
 	closed atomic.Bool
+	// closing is closed when Close is called, to stop the workers of the client
+	closing chan struct{}
+	workers sync.WaitGroup
 
 	heartbeatStopChan chan struct{}
 	heartbeatStart    sync.Once
@@ -70,46 +92,152 @@ type Client struct {
 	leaseMux   sync.RWMutex
 	leases     map[string]*Lease
 	leaseNames []string
+
+	resources     map[string]*ResourceLease
+	resourceNames []string
+
+	permits     map[string]*permit
+	permitNames []string
+	elections   map[string]*Leadership
 }
 
-func (c *Client) Lease(name string, size int) *Lease {
+// Lease returns the named Lease with values in the range 0 to size.
+//
+// The size of a lease is stored the first time the lease is used. All later
+// calls must use the same size, or ErrSizeMismatch is returned.
+// The options are applied when the client first uses the Lease.
+func (c *Client) Lease(name string, size int, options ...LeaseOption) (*Lease, error) {
 	c.leaseMux.Lock()
 	defer c.leaseMux.Unlock()
 
 	if l, ok := c.leases[name]; ok {
-		return l
+		if l.Size() != size {
+			return nil, fmt.Errorf("[dbleases] lease %q has size %d, got %d: %w", name, l.Size(), size, ErrSizeMismatch)
+		}
+		return l, nil
+	}
+
+	if size <= 0 {
+		return nil, fmt.Errorf("[dbleases] lease %q must have a positive size: %d", name, size)
+	}
+
+	o := defaultLeaseOptions()
+	for _, opt := range options {
+		opt(&o)
+	}
+
+	if err := o.validate(); err != nil {
+		return nil, err
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), c.opt.heartbeatTimeout)
 	defer cancel()
 
-	c.leases[name] = newLease(c, name, int(size))
+	definition, err := c.repo.InsertLeaseDefinition(ctx, name, size, o.strategy.Name(), o.fixedSize)
+	if err != nil {
+		return nil, fmt.Errorf("[dbleases] failed to define lease %q: %w", name, err)
+	}
+
+	if definition.Size != size {
+		return nil, fmt.Errorf("[dbleases] lease %q is defined with size %d, got %d: %w", name, definition.Size, size, ErrSizeMismatch)
+	}
+
+	if definition.Strategy != o.strategy.Name() {
+		return nil, fmt.Errorf("[dbleases] lease %q is defined with strategy %q, got %q: %w", name, definition.Strategy, o.strategy.Name(), ErrStrategyMismatch)
+	}
+
+	c.leases[name] = newLease(c, definition, o)
 	c.leaseNames = append(c.leaseNames, name)
 
-	err := c.registerLease(ctx, lease.Request{
-		ClientID:  c.ID,
-		LeaseName: name,
-		Value:     c.leases[name].ringValue[0],
-		Status:    lease.Pending,
-	})
-	if err != nil {
-		c.opt.logger.ErrorfContext(context.Background(), "Failed to register lease %q for client %s: %s", name, c.ID, err)
+	for _, value := range c.leases[name].ringValue {
+		err = c.registerLease(ctx, lease.Request{
+			ClientID:  c.ID,
+			LeaseName: name,
+			Value:     value,
+			Status:    o.status(),
+		})
+		if err != nil {
+			c.opt.logger.ErrorfContext(context.Background(), "Failed to register lease %q for client %s: %s", name, c.ID, err)
+		}
 	}
 
 	c.heartbeatStart.Do(c.startHeartbeat)
-	return c.leases[name]
+	return c.leases[name], nil
 }
 
+// Resize the named Lease to a new size.
+//
+// The ring values of all clients are remapped to the new size, and a new generation
+// of the Lease is started. Until every client has had a heartbeat with the new generation,
+// all clients report no values, so no value is owned by two clients during the switch.
+// Clients using the Lease with the previous size get ErrSizeMismatch.
+//
+// Leases with a size given by their type, such as a KeyspaceLease, a ResourceLease
+// or a Mutex, cannot be resized and return ErrFixedSize.
+func (c *Client) Resize(ctx context.Context, name string, size int) error {
+	if size <= 0 {
+		return fmt.Errorf("[dbleases] lease %q must have a positive size: %d", name, size)
+	}
+
+	definitions, err := c.repo.GetLeaseDefinitions(ctx, []string{name})
+	if err != nil {
+		return fmt.Errorf("[dbleases] failed to resize lease %q: %w", name, err)
+	}
+
+	for _, definition := range definitions {
+		if definition.FixedSize {
+			return fmt.Errorf("[dbleases] lease %q has a size fixed by its type: %w", name, ErrFixedSize)
+		}
+	}
+
+	// the update also skips fixed sizes, should the definition change in between
+	definition, err := c.repo.UpdateLeaseSize(ctx, name, size, c.opt.ttl)
+	if err != nil {
+		return fmt.Errorf("[dbleases] failed to resize lease %q: %w", name, err)
+	}
+
+	c.leaseMux.RLock()
+	defer c.leaseMux.RUnlock()
+	if l, ok := c.leases[name]; ok {
+		l.setDefinition(ctx, definition)
+	}
+
+	return nil
+}
+
+// Close the client. Runners of the client are stopped, and Close waits for their
+// workers to return before the leases of the client are deleted.
 func (c *Client) Close() error {
 	if c.closed.Load() {
 		return nil
 	}
+	c.leaseMux.Lock()
+	close(c.closing)
+	c.leaseMux.Unlock()
+	c.workers.Wait()
+
 	c.heartbeatStopChan <- struct{}{}
 	<-c.heartbeatStopChan
 	c.closed.Store(true)
 	return nil
 }
 
+// addWorker to the workers Close waits for, unless the client is closing.
+// The worker must call c.workers.Done when it returns.
+func (c *Client) addWorker() error {
+	c.leaseMux.Lock()
+	defer c.leaseMux.Unlock()
+
+	select {
+	case <-c.closing:
+		return fmt.Errorf("[dbleases] client %s is closed", c.ID)
+	default:
+	}
+
+	c.workers.Add(1)
+	return nil
+}
+
 func (c *Client) startHeartbeat() {
 	pump := func() {
 		ctx, cancel := context.WithTimeout(context.Background(), c.opt.heartbeatTimeout)
@@ -153,31 +281,101 @@ func (c *Client) heartbeat(ctx context.Context) error {
 	c.leaseMux.RLock()
 	defer c.leaseMux.RUnlock()
 
-	allLeases, err := c.repo.GetAndRefreshLeases(ctx, c.leaseNames, c.ID, c.opt.ttl)
+	// the entries expire ttl after the refresh, which is no earlier than when it was sent
+	sent := time.Now()
+	names := append(append([]string{}, c.leaseNames...), c.permitNames...)
+	allLeases, err := c.repo.GetAndRefreshLeases(ctx, names, c.ID, c.opt.ttl)
+	if err != nil {
+		return err
+	}
+	for _, l := range c.leases {
+		l.setRefreshed(sent)
+	}
+	// Definitions are read after the ring. A resize between the two reads is then
+	// seen as an inactive definition, and never as an old size for a remapped ring.
+	definitions, err := c.repo.GetLeaseDefinitions(ctx, c.leaseNames)
 	if err != nil {
 		return err
 	}
+
+	for _, definition := range definitions {
+		if l, ok := c.leases[definition.Name]; ok {
+			l.setDefinition(ctx, definition)
+		}
+	}
+
 	leasesByName := split.By(lease.Ring(allLeases), func(lease lease.Info) string {
 		return lease.Name
 	})
 
+	c.refreshPermits(ctx, sent, leasesByName)
+
 	for name, leases := range leasesByName {
+		if _, ok := c.permits[name]; ok {
+			continue
+		}
+
 		l, ok := c.leases[name]
 		if !ok {
 			c.opt.logger.ErrorfContext(ctx, "[dbleases] Unexpected lease: %s", name)
 			continue
 		}
 
-		report := leases.Analyze(c.ID, l.size)
+		report, ok := l.analyze(leases)
+		if !ok {
+			l.setValues(ctx, lease.Set{})
+			continue
+		}
 
-		l.setValues(ctx, report.Values)
+		l.setReport(ctx, report)
 		c.approveLeases(ctx, report.Approvals)
-		c.registerLeaseRequests(ctx, report.Balance)
+		if l.allowBalance(leases, report.Balance) {
+			c.registerLeaseRequests(ctx, report.Balance)
+		}
+		for _, takeover := range report.Takeovers {
+			c.registerLeaseRequests(ctx, &takeover)
+		}
+		c.registerLeaseRequests(ctx, l.standbyRequest(leases))
+	}
+
+	return c.assignResources(ctx)
+}
+
+// assignResources of the resource leases to their members, after the members are updated by the heartbeat.
+func (c *Client) assignResources(ctx context.Context) error {
+	if len(c.resourceNames) == 0 {
+		return nil
+	}
+
+	resources, err := c.repo.GetResources(ctx, c.resourceNames)
+	if err != nil {
+		return err
+	}
+
+	keysByName := make(map[string][]string)
+	for _, resource := range resources {
+		keysByName[resource.Name] = append(keysByName[resource.Name], resource.Key)
+	}
+
+	for name, r := range c.resources {
+		r.setResources(ctx, keysByName[name])
 	}
 
 	return nil
 }
 
+// refreshPermits from the entries of a heartbeat, and release entries of permits the client no longer holds.
+func (c *Client) refreshPermits(ctx context.Context, sent time.Time, entriesByName map[string]lease.Ring) {
+	for name, p := range c.permits {
+		for _, value := range p.refresh(sent, entriesByName[name]) {
+			err := c.repo.ReleasePermit(ctx, name, c.ID, value)
+			if err != nil {
+				c.opt.logger.ErrorfContext(ctx, "[dbleases] Failed releasing lost %s %q for client %s: %s", p.kind, name, c.ID, err)
+			}
+		}
+	}
+}
+
 func (c *Client) registerLeaseRequests(ctx context.Context, request *lease.Request) {
 	//c.opt.logger.InfofContext(ctx, "[dbleases] Balancing %#v", request)
 	if request == nil {
@@ -191,7 +389,16 @@ func (c *Client) registerLeaseRequests(ctx context.Context, request *lease.Reque
 }
 
 func (c *Client) registerLease(ctx context.Context, r lease.Request) error {
-	return c.repo.InsertLease(ctx, r.ClientID, r.LeaseName, r.Value, c.opt.ttl, r.Status)
+	return c.repo.InsertLease(ctx, lease.Info{
+		Name:          r.LeaseName,
+		ClientID:      r.ClientID,
+		Status:        r.Status,
+		Value:         r.Value,
+		Weight:        c.opt.weight,
+		MaxPartitions: c.leases[r.LeaseName].opt.maxPartitions,
+		Zone:          c.opt.zone,
+		Metadata:      c.opt.metadata,
+	}, c.opt.ttl)
 }
 
 func (c *Client) approveLeases(ctx context.Context, approvals []lease.Info) {
@@ -212,7 +419,13 @@ func (c *Client) cleanup(ctx context.Context) error {
 	defer c.leaseMux.Unlock()
 
 	for _, l := range c.leases {
-		l.setValues(ctx, nil)
+		l.setValues(ctx, lease.Set{})
+	}
+	for _, r := range c.resources {
+		r.setResources(ctx, nil)
+	}
+	for _, p := range c.permits {
+		p.close()
 	}
 
 	return c.repo.DeleteLeases(ctx, c.ID)
@@ -0,0 +1,14 @@
+package dbleases
+
+import "time"
+
+// newTestClient without a database, as NewClient creates it. The test options are applied
+// after a ttl of a second, a heartbeat of 10ms and disabled logging.
+func newTestClient(clientID string, repo Repository, options ...Option) *Client {
+	o := defaultOptions()
+	for _, opt := range append([]Option{WithTTL(time.Second), WithHeartbeat(time.Millisecond * 10), WithLoggingDisabled()}, options...) {
+		opt(&o)
+	}
+
+	return newClient(nil, clientID, o, repo)
+}
@@ -0,0 +1,184 @@
+package dbleases
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// electionStrategy is the strategy name stored in the definition of an election.
+const electionStrategy = "election"
+
+// Leadership is the campaign of the client in a named election. At most one client
+// is leader of an election at a time.
+//
+// The leader holds the single permit of the election, refreshed by the heartbeat of
+// the client like a Mutex. Other clients campaign on each heartbeat interval, and
+// take over when the leader resigns, closes or lets its permit expire.
+type Leadership struct {
+	client *Client
+	permit *permit
+
+	stop    context.CancelFunc
+	done    chan struct{}
+	changes chan bool
+
+	mu     sync.Mutex
+	leader bool
+	lost   context.Context
+	err    error
+}
+
+// Elect starts a campaign of the client in the named election. The campaign runs until
+// ctx is done, Resign is called or the client is closed. A client campaigns at most once
+// at a time in an election. A name used by an election cannot be used by a Lease.
+func (c *Client) Elect(ctx context.Context, name string) (*Leadership, error) {
+	err := c.addWorker()
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := c.permit(name, electionStrategy, 1)
+	if err != nil {
+		c.workers.Done()
+		return nil, err
+	}
+
+	c.leaseMux.Lock()
+	defer c.leaseMux.Unlock()
+
+	if _, ok := c.elections[name]; ok {
+		c.workers.Done()
+		return nil, fmt.Errorf("[dbleases] client %s already campaigns in election %q", c.ID, name)
+	}
+
+	campaignCtx, stop := context.WithCancel(ctx)
+	l := &Leadership{
+		client:  c,
+		permit:  p,
+		stop:    stop,
+		done:    make(chan struct{}),
+		changes: make(chan bool, 1),
+	}
+	c.elections[name] = l
+
+	go l.campaign(campaignCtx)
+
+	return l, nil
+}
+
+// IsLeader reports if the client is the leader of the election.
+func (l *Leadership) IsLeader() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.leader && l.lost.Err() == nil
+}
+
+// Changes of the leadership. The channel receives true when the client becomes the leader,
+// and false when it is no longer the leader. Only the latest change is kept for a slow
+// receiver. The channel is closed when the campaign ends.
+func (l *Leadership) Changes() <-chan bool {
+	return l.changes
+}
+
+// Term of the latest leadership of the client. Terms increase each time any client becomes
+// the leader, so it can be used as a fencing token like Mutex.Token.
+func (l *Leadership) Term() int64 {
+	return l.permit.token()
+}
+
+// Resign ends the campaign and releases the leadership if the client is the leader.
+// Call Client.Elect again to start a new campaign.
+func (l *Leadership) Resign() error {
+	l.stop()
+	<-l.done
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// campaign for the leadership until ctx is done or the client is closed.
+func (l *Leadership) campaign(ctx context.Context) {
+	defer l.client.workers.Done()
+	defer close(l.done)
+	defer close(l.changes)
+	defer l.end()
+
+	for {
+		ok, err := l.permit.tryAcquire(ctx)
+		if err != nil && ctx.Err() == nil {
+			l.client.opt.logger.ErrorfContext(ctx, "[dbleases] Campaign in election %q failed for client %s: %s", l.permit.name, l.client.ID, err)
+		}
+
+		if ok {
+			lost := l.permit.context()
+			l.setLeader(true, lost)
+
+			select {
+			case <-lost.Done():
+				l.setLeader(false, nil)
+				continue
+			case <-ctx.Done():
+				l.resign()
+				return
+			case <-l.client.closing:
+				l.setLeader(false, nil)
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-l.client.closing:
+			return
+		case <-time.After(l.client.opt.heartbeat):
+		}
+	}
+}
+
+// resign the leadership when the campaign ends.
+func (l *Leadership) resign() {
+	ctx, cancel := context.WithTimeout(context.Background(), l.client.opt.heartbeatTimeout)
+	defer cancel()
+
+	err := l.permit.release(ctx)
+
+	l.mu.Lock()
+	l.err = err
+	l.mu.Unlock()
+
+	l.setLeader(false, nil)
+}
+
+// setLeader and publish the change, replacing a change that is not yet received.
+// The context of a new leadership is lost, which is done when the permit is lost.
+func (l *Leadership) setLeader(leader bool, lost context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.leader == leader {
+		return
+	}
+
+	l.leader = leader
+	if leader {
+		l.lost = lost
+	}
+	l.client.opt.logger.InfofContext(context.Background(), "[dbleases] Client %s leader of election %q: %t", l.client.ID, l.permit.name, leader)
+
+	select {
+	case <-l.changes:
+	default:
+	}
+	l.changes <- leader
+}
+
+// end the campaign, so the client can campaign again.
+func (l *Leadership) end() {
+	l.client.leaseMux.Lock()
+	defer l.client.leaseMux.Unlock()
+	delete(l.client.elections, l.permit.name)
+}
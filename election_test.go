@@ -0,0 +1,152 @@
+package dbleases
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyuff/dbleases/internal/assert"
+	"github.com/kyuff/dbleases/internal/lease"
+)
+
+// permitRepository holds a single permit in memory. Other methods of the Repository are not implemented.
+type permitRepository struct {
+	Repository
+
+	mu     sync.Mutex
+	holder string
+	term   int64
+}
+
+func (r *permitRepository) AcquirePermit(_ context.Context, _ string, clientID string, _ time.Duration, _ int) (lease.Permit, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.holder != "" {
+		return lease.Permit{}, false, nil
+	}
+
+	r.holder = clientID
+	r.term++
+	return lease.Permit{Value: 0, Term: r.term}, true, nil
+}
+
+func (r *permitRepository) ReleasePermit(_ context.Context, _ string, clientID string, _ int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.holder == clientID {
+		r.holder = ""
+	}
+
+	return nil
+}
+
+func TestElection(t *testing.T) {
+	var (
+		newClient = func(clientID string, repo *permitRepository) *Client {
+			c := newTestClient(clientID, repo, WithTTL(time.Minute))
+			c.permits["election-a"] = &permit{client: c, kind: electionStrategy, name: "election-a", permits: 1}
+			return c
+		}
+		receive = func(t *testing.T, changes <-chan bool) (bool, bool) {
+			t.Helper()
+			select {
+			case leader, ok := <-changes:
+				return leader, ok
+			case <-time.After(time.Second):
+				t.Fatal("no change of leadership")
+				return false, false
+			}
+		}
+	)
+
+	t.Run("should become leader of a free election", func(t *testing.T) {
+		// arrange
+		var (
+			repo   = &permitRepository{term: 4}
+			client = newClient("my-client", repo)
+		)
+
+		// act
+		sut, err := client.Elect(context.Background(), "election-a")
+		assert.NoError(t, err)
+
+		// assert
+		leader, _ := receive(t, sut.Changes())
+		assert.Equal(t, true, leader)
+		assert.Equal(t, true, sut.IsLeader())
+		assert.Equal(t, int64(5), sut.Term())
+		assert.NoError(t, sut.Resign())
+	})
+
+	t.Run("should not campaign twice in an election", func(t *testing.T) {
+		// arrange
+		var (
+			client = newClient("my-client", &permitRepository{})
+		)
+		sut, err := client.Elect(context.Background(), "election-a")
+		assert.NoError(t, err)
+		defer sut.Resign()
+
+		// act
+		_, err = client.Elect(context.Background(), "election-a")
+
+		// assert
+		assert.Error(t, err)
+	})
+
+	t.Run("should release the leadership on resign", func(t *testing.T) {
+		// arrange
+		var (
+			repo    = &permitRepository{}
+			clientA = newClient("client-a", repo)
+			clientB = newClient("client-b", repo)
+		)
+		leaderA, err := clientA.Elect(context.Background(), "election-a")
+		assert.NoError(t, err)
+		leader, _ := receive(t, leaderA.Changes())
+		assert.Equal(t, true, leader)
+
+		leaderB, err := clientB.Elect(context.Background(), "election-a")
+		assert.NoError(t, err)
+		defer leaderB.Resign()
+
+		// act
+		err = leaderA.Resign()
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, false, leaderA.IsLeader())
+		leader, ok := receive(t, leaderA.Changes())
+		assert.Equal(t, false, leader)
+		assert.Equal(t, true, ok)
+		_, ok = receive(t, leaderA.Changes())
+		assert.Equal(t, false, ok)
+
+		leader, _ = receive(t, leaderB.Changes())
+		assert.Equal(t, true, leader)
+		assert.Equal(t, int64(2), leaderB.Term())
+	})
+
+	t.Run("should end the campaign when the client is closed", func(t *testing.T) {
+		// arrange
+		var (
+			client = newClient("my-client", &permitRepository{})
+		)
+		sut, err := client.Elect(context.Background(), "election-a")
+		assert.NoError(t, err)
+		leader, _ := receive(t, sut.Changes())
+		assert.Equal(t, true, leader)
+
+		// act
+		close(client.closing)
+		client.workers.Wait()
+
+		// assert
+		assert.Equal(t, false, sut.IsLeader())
+		leader, _ = receive(t, sut.Changes())
+		assert.Equal(t, false, leader)
+		_, err = client.Elect(context.Background(), "election-a")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,21 @@
+package dbleases
+
+import "errors"
+
+// ErrSizeMismatch is returned when a lease is used with a different size than it was defined with.
+var ErrSizeMismatch = errors.New("lease size mismatch")
+
+// ErrStrategyMismatch is returned when a lease is used with a different Strategy than it was defined with.
+var ErrStrategyMismatch = errors.New("lease strategy mismatch")
+
+// ErrFixedSize is returned when a lease with a size given by its type, such as a KeyspaceLease, is resized.
+var ErrFixedSize = errors.New("lease size is fixed")
+
+// ErrNotOwned is the cause of a Lease.Context that is cancelled, because the value is not owned by the client.
+var ErrNotOwned = errors.New("lease value not owned")
+
+// ErrLeaseExpired is the cause of a Lease.Context that passed its deadline, because the lease was not refreshed in time.
+var ErrLeaseExpired = errors.New("lease expired")
+
+// ErrLockLost is the cause of a Mutex.Context or Semaphore.Context that is cancelled, because the lock was not refreshed in time.
+var ErrLockLost = errors.New("lock lost")
@@ -110,6 +110,20 @@ Expected: %v
 	return false
 }
 
+func DeepEqualSlice[V any, T []V](t *testing.T, expected, got T) bool {
+	t.Helper()
+	if len(expected) == len(got) && (len(expected) == 0 || reflect.DeepEqual(expected, got)) {
+		return true
+	}
+
+	t.Logf(`
+Slices was not equal
+Expected: %v
+     Got: %v`, expected, got)
+	t.Fail()
+	return false
+}
+
 func NotEqual[T comparable](t *testing.T, unexpected, got T) bool {
 	t.Helper()
 	if unexpected == got {
@@ -16,3 +16,34 @@ func Hash(s string) int {
 func Mod(s string, mod int) int {
 	return Hash(s) % mod
 }
+
+// Key hashes a key with 32 bit FNV-1, the same hash as Hash.
+func Key[K ~string | ~[]byte](key K) uint32 {
+	h := fnv.New32()
+	_, _ = h.Write([]byte(key))
+	return h.Sum32()
+}
+
+// Key64 hashes a key with 64 bit FNV-1a.
+func Key64[K ~string | ~[]byte](key K) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum64()
+}
+
+// Score of a value for a member, used to rank members in rendezvous hashing.
+//
+// The member is hashed with 64 bit FNV-1a and combined with the value through the
+// splitmix64 finalizer, so members with similar names still get independent scores.
+func Score(member string, value int) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(member))
+	return mix(h.Sum64() ^ mix(uint64(value)))
+}
+
+func mix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
@@ -24,6 +24,23 @@ func TestMod(t *testing.T) {
 	t.Logf("Hash: %q", findCorrectMod(15, 100))
 }
 
+func TestKey(t *testing.T) {
+	assert.Equal(t, uint32(1423569895), hash.Key("stream type"))
+	assert.Equal(t, uint32(1423569895), hash.Key([]byte("stream type")))
+}
+
+func TestKey64(t *testing.T) {
+	assert.Equal(t, uint64(0xcbf29ce484222325), hash.Key64(""))
+	assert.Equal(t, uint64(0xaf63dc4c8601ec8c), hash.Key64("a"))
+	assert.Equal(t, hash.Key64("stream type"), hash.Key64([]byte("stream type")))
+}
+
+func TestScore(t *testing.T) {
+	assert.Equal(t, uint64(7566401704046431338), hash.Score("client-a", 0))
+	assert.Equal(t, uint64(779540542133747239), hash.Score("client-a", 1))
+	assert.Equal(t, uint64(12705250631579395229), hash.Score("client-b", 0))
+}
+
 func findCorrectMod(val, max int) []string {
 	const (
 		template = "%s hash %d/%d"
@@ -0,0 +1,19 @@
+package lease
+
+import "time"
+
+// Definition is the shared description of a lease that all clients must agree on.
+//
+// A Definition is replaced by a new Generation when the lease is resized. The new
+// Generation is not Active until all clients have had a heartbeat to drop the values
+// they had in the previous Generation.
+type Definition struct {
+	Name       string
+	Size       int
+	Strategy   string
+	Created    time.Time
+	Generation int
+	Active     bool
+	// FixedSize is set when the size is given by the type of lease, such as a mutex, and cannot be resized
+	FixedSize bool
+}
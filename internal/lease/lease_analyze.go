@@ -2,15 +2,10 @@ package lease
 
 import (
 	"fmt"
-	"math"
 	"sort"
 	"time"
 )
 
-const (
-	balanceThreshold = 5
-)
-
 type Status string
 
 func (s Status) String() string {
@@ -20,6 +15,8 @@ func (s Status) String() string {
 const (
 	Pending Status = "PENDING"
 	Leased  Status = "LEASED"
+	// Standby entries are in the Ring without values, ready to take over from clients that leave.
+	Standby Status = "STANDBY"
 )
 
 type Info struct {
@@ -28,6 +25,15 @@ type Info struct {
 	TTL      time.Time
 	Status   Status
 	Value    int
+	Weight   int
+	// Zone of the client, such as an availability zone
+	Zone string
+	// Metadata published by the client, such as hostname, version and address
+	Metadata map[string]string
+	// Age of the entry in the Ring, measured by the database
+	Age time.Duration
+	// MaxPartitions is the most values the client will own. Zero means no limit.
+	MaxPartitions int
 }
 
 func (i Info) String() string {
@@ -42,9 +48,15 @@ type Request struct {
 }
 
 type Report struct {
-	Values    []int
-	Approvals []Info
-	Balance   *Request
+	Values     Set
+	Unassigned int
+	Approvals  []Info
+	Balance    *Request
+	Takeovers  []Request
+	// Replicas are values owned by clients in other zones, that the client holds a replica of
+	Replicas Set
+	// Members are all clients in the Ring with the values they own
+	Members []Member
 }
 
 type Ring []Info
@@ -78,24 +90,55 @@ func (ring Ring) nextLease(i int) Info {
 // - It is the responsibility of the previous Leased Client to approve the next Pending Client
 // - A Client can lease values by adding a value in a Pending state
 // - A solo Client has a lease on the entire Ring
-// - If there is a need to Balance it must be done by the client with the least value values or the lowest value
+// - A Client with MaxPartitions owns only the first values of its range, leaving the rest unassigned
+// - The unassigned values of a Client with MaxPartitions are taken by the Client with the least values, before balancing
+// - If there is a need to Balance it must be done by the client with the least values relative to its weight
+// - Values are not balanced while an entry in the Ring is younger than the Rebalancing Hold
+// - With a Quorum, a booting Ring is approved at once by the lowest value client when the Quorum is reached
+// - Standby entries have no values. A Standby client takes over the entries of clients that left the Ring
+// - Each zone is balanced to an equal share of the values, which is split between the clients in the zone
+// - Replicas of the values of an entry are held by the next clients in the Ring that are in other zones
 func (ring Ring) Analyze(clientID string, size int) Report {
+	return analyzeRing(Snapshot{
+		ClientID:    clientID,
+		Size:        size,
+		Ring:        ring,
+		Rebalancing: DefaultRebalancing,
+	})
+}
+
+func analyzeRing(snapshot Snapshot) Report {
 	var (
-		report    Report
+		ring      = snapshot.Ring
+		clientID  = snapshot.ClientID
+		size      = snapshot.Size
+		report    = Report{Unassigned: size}
 		clients   = make(map[string]Client)
 		leaseName string
+		ranges    []ringRange
 	)
 	sort.Slice(ring, func(i, j int) bool { return ring[i].Value < ring[j].Value })
 
+	if snapshot.Standby {
+		report.Takeovers = takeovers(snapshot)
+	}
+
+	ring = ring.active()
 	if len(ring) == 0 {
+		report.Members = ringMembers(snapshot.Ring, nil)
 		return report
 	}
 
+	var waitForQuorum = snapshot.Quorum.enabled() && ring.booting()
+	if waitForQuorum {
+		report.Approvals = quorumApprovals(ring, clientID, snapshot.Quorum)
+	}
+
 	for i, lease := range ring {
 		var (
 			next      = ring.nextLease(i)
 			approvals []Info
-			values    []int
+			spans     []Span
 		)
 
 		switch {
@@ -111,16 +154,16 @@ func (ring Ring) Analyze(clientID string, size int) Report {
 
 		if lease.Status == Leased {
 			if next.Value > lease.Value {
-				values = append(values, fromTo(lease.Value, next.Value)...)
+				spans = append(spans, Span{From: lease.Value, To: next.Value})
 			} else {
-				values = append(values, fromTo(0, next.Value)...)
-				values = append(values, fromTo(lease.Value, size)...)
+				spans = append(spans, Span{From: 0, To: next.Value}, Span{From: lease.Value, To: size})
 			}
+
+			ranges = append(ranges, ringRange{lease: lease, spans: spans})
 		}
 
-		if lease.ClientID == clientID {
+		if lease.ClientID == clientID && !waitForQuorum {
 			report.Approvals = append(report.Approvals, approvals...)
-			report.Values = append(report.Values, values...)
 		}
 
 		if _, ok := clients[lease.ClientID]; !ok {
@@ -130,50 +173,190 @@ func (ring Ring) Analyze(clientID string, size int) Report {
 		}
 
 		clients[lease.ClientID] = Client{ID: lease.ClientID,
-			Values: append(clients[lease.ClientID].Values, values...),
+			Spans:         append(clients[lease.ClientID].Spans, spans...),
+			Weight:        lease.Weight,
+			MaxPartitions: lease.MaxPartitions,
+			Zone:          lease.Zone,
 		}
 	}
 
-	if len(clients) < size {
-		report.Balance = analyzeBalance(leaseName, clientID, clients)
+	if snapshot.Replicas > 0 {
+		report.Replicas = replicas(ranges, clientID, snapshot.Replicas)
+	}
+
+	var owned = make(map[string]Set, len(clients))
+	for _, client := range clients {
+		owned[client.ID] = SetOf(client.owned()...)
+		report.Unassigned -= owned[client.ID].Len()
 	}
 
-	sort.Ints(report.Values)
+	report.Values = owned[clientID]
+	report.Members = ringMembers(snapshot.Ring, owned)
+
+	if len(clients) < size && !snapshot.Standby {
+		report.Balance = analyzeBalance(leaseName, clientID, clients, snapshot.Rebalancing, ring.settled(snapshot.Rebalancing.Hold))
+	}
 
 	return report
 }
 
 type Client struct {
-	ID     string
-	Values []int
+	ID string
+	// Spans of values in the order of the entries in the Ring
+	Spans         []Span
+	Weight        int
+	MaxPartitions int
+	Zone          string
+	// zoneWeight is the sum of weights of all clients in the Zone
+	zoneWeight int
+}
+
+// size is the number of values in the spans of the client.
+func (c Client) size() int {
+	return spansLen(c.Spans)
+}
+
+// owned spans of the client, which are the first values of its range up to MaxPartitions.
+func (c Client) owned() []Span {
+	if c.MaxPartitions > 0 {
+		return spansTake(c.Spans, c.MaxPartitions)
+	}
+
+	return c.Spans
+}
+
+// ownedSize is the number of values the client owns, which is at most MaxPartitions.
+func (c Client) ownedSize() int {
+	if c.MaxPartitions > 0 {
+		return min(c.size(), c.MaxPartitions)
+	}
+
+	return c.size()
+}
+
+// surplus is the number of values in the spans of the client beyond its MaxPartitions, which are unassigned.
+func (c Client) surplus() int {
+	return c.size() - c.ownedSize()
+}
+
+// full reports if the client owns as many values as it will take.
+func (c Client) full() bool {
+	return c.MaxPartitions > 0 && c.size() >= c.MaxPartitions
+}
+
+func (c Client) weight() int {
+	if c.Weight < 1 {
+		return 1
+	}
+
+	return c.Weight
+}
+
+// share of the values the client should have relative to other clients.
+// Each zone has an equal share, which is split between its clients by weight.
+// The share is returned as a fraction to keep the calculations in integers.
+func (c Client) share() (numerator, denominator int) {
+	if c.zoneWeight < 1 {
+		return c.weight(), 1
+	}
+
+	return c.weight(), c.zoneWeight
 }
 
-func analyzeBalance(leaseName string, clientID string, clients map[string]Client) *Request {
+// heavier reports if c owns more values relative to its share than other.
+func (c Client) heavier(other Client) bool {
+	cNum, cDen := c.share()
+	oNum, oDen := other.share()
+	return c.ownedSize()*cDen*oNum > other.ownedSize()*oDen*cNum
+}
+
+// withZoneWeights sets the zone weight of all clients.
+func withZoneWeights(clients map[string]Client) map[string]Client {
+	var zones = make(map[string]int)
+	for _, client := range clients {
+		zones[client.Zone] += client.weight()
+	}
+
+	var weighted = make(map[string]Client, len(clients))
+	for id, client := range clients {
+		client.zoneWeight = zones[client.Zone]
+		weighted[id] = client
+	}
+
+	return weighted
+}
+
+// settled reports if all entries in the Ring are at least as old as hold.
+func (ring Ring) settled(hold time.Duration) bool {
+	for _, lease := range ring {
+		if lease.Age < hold {
+			return false
+		}
+	}
+
+	return true
+}
+
+func analyzeBalance(leaseName string, clientID string, clients map[string]Client, rebalancing Rebalancing, settled bool) *Request {
+	clients = withZoneWeights(clients)
 	var (
-		maxClientID string
-		minClientID string
-		maxSize     int
-		minSize     = math.MaxInt
+		maxClient     Client
+		minClient     Client
+		surplusClient Client
+		firstMax      = true
+		firstMin      = true
 	)
-	for clientID, client := range clients {
-		size := len(client.Values)
-		if size > maxSize {
-			maxSize = size
-			maxClientID = clientID
+	for _, client := range clients {
+		if firstMax || client.heavier(maxClient) {
+			maxClient = client
+			firstMax = false
 		}
-		if size < minSize {
-			minSize = size
-			minClientID = clientID
+		// a full client must not take more values, so it is never behind
+		if !client.full() && (firstMin || minClient.heavier(client)) {
+			minClient = client
+			firstMin = false
+		}
+		if client.surplus() > surplusClient.surplus() ||
+			client.surplus() > 0 && client.surplus() == surplusClient.surplus() && client.ID < surplusClient.ID {
+			surplusClient = client
 		}
 	}
 
-	sizeDiff := maxSize - minSize
 	_, clientInRing := clients[clientID]
-	clientIsBehind := minClientID == clientID && sizeDiff > balanceThreshold
+
+	// the surplus of a full client is unassigned, so it is taken before any owned values are moved
+	if surplusClient.surplus() > 0 && (!clientInRing || !firstMin) {
+		if clientInRing && (minClient.ID != clientID || !settled) {
+			return nil
+		}
+
+		adjust := surplusClient.surplus()
+		if clientInRing && minClient.MaxPartitions > 0 {
+			adjust = min(adjust, minClient.MaxPartitions-minClient.ownedSize())
+		}
+
+		return &Request{
+			ClientID:  clientID,
+			LeaseName: leaseName,
+			Value:     spansAt(surplusClient.Spans, surplusClient.size()-adjust),
+			Status:    Pending,
+		}
+	}
+
+	var (
+		maxSize        = maxClient.ownedSize()
+		minSize        = minClient.ownedSize()
+		maxNum, maxDen = maxClient.share()
+		minNum, minDen = minClient.share()
+		// sizeDiff is the difference in values between the two clients, as if they had equal shares
+		sizeDiff = (maxSize*minNum*maxDen - minSize*maxNum*minDen) * 2 / (minNum*maxDen + maxNum*minDen)
+	)
+
+	adjust := rebalancing.limit(sizeDiff / 2)
+	clientIsBehind := minClient.ID == clientID && sizeDiff > rebalancing.Threshold && adjust > 0 && settled
 	systemIsStarted := maxSize > 0
 
 	if systemIsStarted && (!clientInRing || clientIsBehind) {
-		adjust := sizeDiff / 2
 		if !clientInRing {
 			adjust = 1
 		}
@@ -181,18 +364,10 @@ func analyzeBalance(leaseName string, clientID string, clients map[string]Client
 		return &Request{
 			ClientID:  clientID,
 			LeaseName: leaseName,
-			Value:     clients[maxClientID].Values[maxSize-adjust],
+			Value:     spansAt(maxClient.Spans, maxSize-adjust),
 			Status:    Pending,
 		}
 	}
 
 	return nil
 }
-
-func fromTo(from, to int) []int {
-	var items []int
-	for i := from; i < to; i++ {
-		items = append(items, i)
-	}
-	return items
-}
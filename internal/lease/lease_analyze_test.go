@@ -20,8 +20,8 @@ func TestAnalyzeLeases(t *testing.T) {
 		got := sut.Analyze("my-client", 4)
 
 		// assert
-		assert.EqualSlice(t, []int{0, 1, 2, 3}, got.Values)
-		assert.EqualSlice(t, expectApprove, got.Approvals)
+		assert.EqualSlice(t, []int{0, 1, 2, 3}, got.Values.Values())
+		assert.DeepEqualSlice(t, expectApprove, got.Approvals)
 	})
 
 	t.Run("should approve as only lease", func(t *testing.T) {
@@ -39,8 +39,8 @@ func TestAnalyzeLeases(t *testing.T) {
 		got := sut.Analyze("my-client", 4)
 
 		// assert
-		assert.EqualSlice(t, []int{}, got.Values)
-		assert.EqualSlice(t, expectApprove, got.Approvals)
+		assert.EqualSlice(t, []int{}, got.Values.Values())
+		assert.DeepEqualSlice(t, expectApprove, got.Approvals)
 	})
 
 	t.Run("should respect next lease in ring", func(t *testing.T) {
@@ -57,8 +57,8 @@ func TestAnalyzeLeases(t *testing.T) {
 		got := sut.Analyze("my-client", 5)
 
 		// assert
-		assert.EqualSlice(t, []int{2, 3}, got.Values)
-		assert.EqualSlice(t, expectApprove, got.Approvals)
+		assert.EqualSlice(t, []int{2, 3}, got.Values.Values())
+		assert.DeepEqualSlice(t, expectApprove, got.Approvals)
 	})
 
 	t.Run("should approve next client in ring", func(t *testing.T) {
@@ -77,8 +77,8 @@ func TestAnalyzeLeases(t *testing.T) {
 		got := sut.Analyze("my-client", 5)
 
 		// assert
-		assert.EqualSlice(t, []int{2, 3}, got.Values)
-		assert.EqualSlice(t, expectApprove, got.Approvals)
+		assert.EqualSlice(t, []int{2, 3}, got.Values.Values())
+		assert.DeepEqualSlice(t, expectApprove, got.Approvals)
 	})
 
 	t.Run("should approve first pending client", func(t *testing.T) {
@@ -97,8 +97,8 @@ func TestAnalyzeLeases(t *testing.T) {
 		got := sut.Analyze("my-client", 5)
 
 		// assert
-		assert.EqualSlice(t, []int{}, got.Values)
-		assert.EqualSlice(t, expectApprove, got.Approvals)
+		assert.EqualSlice(t, []int{}, got.Values.Values())
+		assert.DeepEqualSlice(t, expectApprove, got.Approvals)
 	})
 
 	t.Run("should let first client approve", func(t *testing.T) {
@@ -115,8 +115,8 @@ func TestAnalyzeLeases(t *testing.T) {
 		got := sut.Analyze("my-client", 5)
 
 		// assert
-		assert.EqualSlice(t, []int{}, got.Values)
-		assert.EqualSlice(t, expectApprove, got.Approvals)
+		assert.EqualSlice(t, []int{}, got.Values.Values())
+		assert.DeepEqualSlice(t, expectApprove, got.Approvals)
 	})
 
 	t.Run("should calculate ring that passes the number end", func(t *testing.T) {
@@ -133,8 +133,8 @@ func TestAnalyzeLeases(t *testing.T) {
 		got := sut.Analyze("my-client", 5)
 
 		// assert
-		assert.EqualSlice(t, []int{0, 1, 4}, got.Values)
-		assert.EqualSlice(t, expectApprove, got.Approvals)
+		assert.EqualSlice(t, []int{0, 1, 4}, got.Values.Values())
+		assert.DeepEqualSlice(t, expectApprove, got.Approvals)
 	})
 
 	t.Run("should respect a larger ring", func(t *testing.T) {
@@ -153,8 +153,8 @@ func TestAnalyzeLeases(t *testing.T) {
 		got := sut.Analyze("my-client", 10)
 
 		// assert
-		assert.EqualSlice(t, []int{6, 7}, got.Values)
-		assert.EqualSlice(t, expectApprove, got.Approvals)
+		assert.EqualSlice(t, []int{6, 7}, got.Values.Values())
+		assert.DeepEqualSlice(t, expectApprove, got.Approvals)
 	})
 
 	t.Run("should recognize a client with multiple spots in the ring", func(t *testing.T) {
@@ -173,8 +173,8 @@ func TestAnalyzeLeases(t *testing.T) {
 		got := sut.Analyze("my-client", 10)
 
 		// assert
-		assert.EqualSlice(t, []int{1, 2, 3, 6, 7}, got.Values)
-		assert.EqualSlice(t, expectApprove, got.Approvals)
+		assert.EqualSlice(t, []int{1, 2, 3, 6, 7}, got.Values.Values())
+		assert.DeepEqualSlice(t, expectApprove, got.Approvals)
 	})
 
 	t.Run("should support when a client is both at the start and end", func(t *testing.T) {
@@ -193,8 +193,8 @@ func TestAnalyzeLeases(t *testing.T) {
 		got := sut.Analyze("my-client", 10)
 
 		// assert
-		assert.EqualSlice(t, []int{0, 1, 2, 3, 8, 9}, got.Values)
-		assert.EqualSlice(t, expectApprove, got.Approvals)
+		assert.EqualSlice(t, []int{0, 1, 2, 3, 8, 9}, got.Values.Values())
+		assert.DeepEqualSlice(t, expectApprove, got.Approvals)
 	})
 
 	t.Run("should approve next in the ring that is pending", func(t *testing.T) {
@@ -215,8 +215,8 @@ func TestAnalyzeLeases(t *testing.T) {
 		got := sut.Analyze("my-client", 10)
 
 		// assert
-		assert.EqualSlice(t, []int{4, 5}, got.Values)
-		assert.EqualSlice(t, expectApprove, got.Approvals)
+		assert.EqualSlice(t, []int{4, 5}, got.Values.Values())
+		assert.DeepEqualSlice(t, expectApprove, got.Approvals)
 	})
 
 	t.Run("should ignore client that is before in the ring and pending", func(t *testing.T) {
@@ -235,8 +235,8 @@ func TestAnalyzeLeases(t *testing.T) {
 		got := sut.Analyze("my-client", 10)
 
 		// assert
-		assert.EqualSlice(t, []int{6, 7}, got.Values)
-		assert.EqualSlice(t, expectApprove, got.Approvals)
+		assert.EqualSlice(t, []int{6, 7}, got.Values.Values())
+		assert.DeepEqualSlice(t, expectApprove, got.Approvals)
 	})
 
 	t.Run("should approve pending that is located after number end in the ring", func(t *testing.T) {
@@ -257,8 +257,8 @@ func TestAnalyzeLeases(t *testing.T) {
 		got := sut.Analyze("my-client", 10)
 
 		// assert
-		assert.EqualSlice(t, []int{0, 8, 9}, got.Values)
-		assert.EqualSlice(t, expectApprove, got.Approvals)
+		assert.EqualSlice(t, []int{0, 8, 9}, got.Values.Values())
+		assert.DeepEqualSlice(t, expectApprove, got.Approvals)
 	})
 
 	t.Run("should request balanced lease expansion", func(t *testing.T) {
@@ -392,4 +392,160 @@ func TestAnalyzeLeases(t *testing.T) {
 			assert.Equal(t, expectBalance, *got.Balance)
 		}
 	})
+
+	t.Run("should request lease expansion proportional to weight", func(t *testing.T) {
+		// arrange
+		var (
+			sut = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased, Weight: 1},   // 50/90
+				{ClientID: "my-client", Name: "lease-a", Value: 50, Status: Leased, Weight: 2}, // 40/90
+			}
+			expectBalance = Request{
+				ClientID: "my-client", LeaseName: "lease-a", Value: 30, Status: Pending,
+			}
+		)
+
+		// act
+		got := sut.Analyze("my-client", 90)
+
+		// assert
+		if assert.NotNil(t, got.Balance) {
+			assert.Equal(t, expectBalance, *got.Balance)
+		}
+	})
+
+	t.Run("should not request lease expansion when balanced by weight", func(t *testing.T) {
+		// arrange
+		var (
+			sut = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased, Weight: 2},   // 60/90
+				{ClientID: "my-client", Name: "lease-a", Value: 60, Status: Leased, Weight: 1}, // 30/90
+			}
+		)
+
+		// act
+		got := sut.Analyze("my-client", 90)
+
+		// assert
+		assert.Nil(t, got.Balance)
+	})
+
+	t.Run("should only own max partitions as solo client", func(t *testing.T) {
+		// arrange
+		var (
+			sut = Ring{
+				{ClientID: "my-client", Name: "lease-a", Value: 1, Status: Leased, MaxPartitions: 2},
+			}
+		)
+
+		// act
+		got := sut.Analyze("my-client", 10)
+
+		// assert
+		assert.EqualSlice(t, []int{0, 1}, got.Values.Values())
+		assert.Equal(t, 8, got.Unassigned)
+		assert.Nil(t, got.Balance)
+	})
+
+	t.Run("should not request lease expansion when at max partitions", func(t *testing.T) {
+		// arrange
+		var (
+			sut = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased},
+				{ClientID: "my-client", Name: "lease-a", Value: 80, Status: Leased, MaxPartitions: 20},
+			}
+		)
+
+		// act
+		got := sut.Analyze("my-client", 100)
+
+		// assert
+		assert.EqualSlice(t, SetOf(Span{From: 80, To: 100}).Values(), got.Values.Values())
+		assert.Equal(t, 0, got.Unassigned)
+		assert.Nil(t, got.Balance)
+	})
+
+	t.Run("should request unassigned values from a client at max partitions", func(t *testing.T) {
+		// arrange
+		var (
+			sut = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased, MaxPartitions: 10},
+				{ClientID: "my-client", Name: "lease-a", Value: 80, Status: Leased},
+			}
+			expectBalance = Request{
+				ClientID: "my-client", LeaseName: "lease-a", Value: 10, Status: Pending,
+			}
+		)
+
+		// act
+		got := sut.Analyze("my-client", 100)
+
+		// assert
+		assert.Equal(t, 70, got.Unassigned)
+		if assert.NotNil(t, got.Balance) {
+			assert.Equal(t, expectBalance, *got.Balance)
+		}
+	})
+
+	t.Run("should request unassigned values from a client at max partitions with equal ranges", func(t *testing.T) {
+		// arrange
+		var (
+			sut = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased, MaxPartitions: 10},
+				{ClientID: "my-client", Name: "lease-a", Value: 50, Status: Leased},
+			}
+			expectBalance = Request{
+				ClientID: "my-client", LeaseName: "lease-a", Value: 10, Status: Pending,
+			}
+		)
+
+		// act
+		got := sut.Analyze("my-client", 100)
+
+		// assert
+		assert.Equal(t, 40, got.Unassigned)
+		if assert.NotNil(t, got.Balance) {
+			assert.Equal(t, expectBalance, *got.Balance)
+		}
+	})
+
+	t.Run("should request no more unassigned values than the client has room for", func(t *testing.T) {
+		// arrange
+		var (
+			sut = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased, MaxPartitions: 10},
+				{ClientID: "my-client", Name: "lease-a", Value: 50, Status: Leased, MaxPartitions: 60},
+			}
+			expectBalance = Request{
+				ClientID: "my-client", LeaseName: "lease-a", Value: 40, Status: Pending,
+			}
+		)
+
+		// act
+		got := sut.Analyze("my-client", 100)
+
+		// assert
+		if assert.NotNil(t, got.Balance) {
+			assert.Equal(t, expectBalance, *got.Balance)
+		}
+	})
+
+	t.Run("should own all values after taking the unassigned values", func(t *testing.T) {
+		// arrange
+		var (
+			sut = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased, MaxPartitions: 10},
+				{ClientID: "my-client", Name: "lease-a", Value: 10, Status: Leased},
+				{ClientID: "my-client", Name: "lease-a", Value: 50, Status: Leased},
+			}
+		)
+
+		// act
+		got := sut.Analyze("my-client", 100)
+
+		// assert
+		assert.Equal(t, 0, got.Unassigned)
+		assert.Equal(t, 90, got.Values.Len())
+		assert.Nil(t, got.Balance)
+	})
 }
@@ -0,0 +1,85 @@
+package lease
+
+import (
+	"sort"
+	"time"
+)
+
+// Member of a Ring with the values it owns.
+type Member struct {
+	ClientID string
+	Values   Set
+	// Status is Leased if any entry of the client is Leased, then Pending and last Standby
+	Status Status
+	// TTL is the latest expiry of the entries of the client in the database
+	TTL      time.Time
+	Zone     string
+	Metadata map[string]string
+}
+
+// ringMembers of the Ring sorted by ClientID, with the values owned by each client.
+func ringMembers(ring Ring, owned map[string]Set) []Member {
+	var (
+		byID   = make(map[string]*Member)
+		result []Member
+	)
+	for _, entry := range ring {
+		member, ok := byID[entry.ClientID]
+		if !ok {
+			member = &Member{
+				ClientID: entry.ClientID,
+				Status:   entry.Status,
+				TTL:      entry.TTL,
+				Zone:     entry.Zone,
+				Metadata: entry.Metadata,
+				Values:   owned[entry.ClientID],
+			}
+			byID[entry.ClientID] = member
+		}
+
+		if statusRank(entry.Status) > statusRank(member.Status) {
+			member.Status = entry.Status
+		}
+		if entry.TTL.After(member.TTL) {
+			member.TTL = entry.TTL
+		}
+	}
+
+	for _, member := range byID {
+		result = append(result, *member)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ClientID < result[j].ClientID })
+
+	return result
+}
+
+// CopyMembers and their metadata. Values are immutable Sets, and are shared with the copy.
+func CopyMembers(members []Member) []Member {
+	if members == nil {
+		return nil
+	}
+
+	var copied = make([]Member, len(members))
+	for i, member := range members {
+		copied[i] = member
+		if member.Metadata != nil {
+			copied[i].Metadata = make(map[string]string, len(member.Metadata))
+			for key, value := range member.Metadata {
+				copied[i].Metadata[key] = value
+			}
+		}
+	}
+
+	return copied
+}
+
+func statusRank(status Status) int {
+	switch status {
+	case Leased:
+		return 2
+	case Pending:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,84 @@
+package lease
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyuff/dbleases/internal/assert"
+)
+
+func TestMembers(t *testing.T) {
+	var (
+		now  = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		ring = func() Ring {
+			return Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased, TTL: now},
+				{ClientID: "client-2", Name: "lease-a", Value: 4, Status: Pending, TTL: now, Zone: "zone-b"},
+				{ClientID: "client-1", Name: "lease-a", Value: 6, Status: Leased, TTL: now.Add(time.Second)},
+				{ClientID: "standby", Name: "lease-a", Value: -5, Status: Standby, TTL: now},
+			}
+		}
+		expected = []Member{
+			{ClientID: "client-1", Values: NewSet(0, 1, 2, 3, 6, 7), Status: Leased, TTL: now.Add(time.Second)},
+			{ClientID: "client-2", Values: Set{}, Status: Pending, TTL: now, Zone: "zone-b"},
+			{ClientID: "standby", Values: Set{}, Status: Standby, TTL: now},
+		}
+	)
+
+	t.Run("should report all members with the ring strategy", func(t *testing.T) {
+		// act
+		got := RingStrategy{}.Analyze(Snapshot{ClientID: "client-2", Size: 8, Ring: ring()})
+
+		// assert
+		assert.DeepEqualSlice(t, expected, got.Members)
+	})
+
+	t.Run("should report all members with the rendezvous strategy", func(t *testing.T) {
+		// act
+		got := RendezvousStrategy{}.Analyze(Snapshot{ClientID: "client-2", Size: 8, Ring: ring()})
+
+		// assert
+		if assert.Equal(t, 3, len(got.Members)) {
+			assert.Equal(t, 8-got.Unassigned, got.Members[0].Values.Len())
+			assert.Equal(t, Leased, got.Members[0].Status)
+			assert.Equal(t, Pending, got.Members[1].Status)
+			assert.Equal(t, Standby, got.Members[2].Status)
+		}
+	})
+
+	t.Run("should report members of a standby only ring", func(t *testing.T) {
+		// arrange
+		var (
+			expected = []Member{
+				{ClientID: "standby", Values: Set{}, Status: Standby, TTL: now},
+			}
+		)
+
+		// act
+		got := RingStrategy{}.Analyze(Snapshot{ClientID: "standby", Size: 8, Ring: Ring{
+			{ClientID: "standby", Name: "lease-a", Value: -5, Status: Standby, TTL: now},
+		}})
+
+		// assert
+		assert.DeepEqualSlice(t, expected, got.Members)
+	})
+
+	t.Run("should copy members without sharing metadata", func(t *testing.T) {
+		// arrange
+		var (
+			members = []Member{
+				{ClientID: "client-1", Values: NewSet(1, 2), Metadata: map[string]string{"host": "a"}},
+			}
+		)
+
+		// act
+		got := CopyMembers(members)
+		got[0].Metadata["host"] = "b"
+		got[0].ClientID = "client-2"
+
+		// assert
+		assert.Equal(t, "a", members[0].Metadata["host"])
+		assert.Equal(t, "client-1", members[0].ClientID)
+		assert.Equal(t, true, got[0].Values.Equal(members[0].Values))
+	})
+}
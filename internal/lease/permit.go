@@ -0,0 +1,8 @@
+package lease
+
+// Permit is a value of a lease held by a client of a Mutex or a Semaphore.
+type Permit struct {
+	Value int
+	// Term of the lease when the permit was acquired. It increases with each acquired permit.
+	Term int64
+}
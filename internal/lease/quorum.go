@@ -0,0 +1,54 @@
+package lease
+
+import "time"
+
+// Quorum holds back the first assignment of values in a Ring until enough clients are present.
+type Quorum struct {
+	// MinMembers is the number of clients in the Ring before values are assigned.
+	MinMembers int
+	// Window is the time after the first entry in the Ring, after which values are assigned regardless of MinMembers.
+	Window time.Duration
+}
+
+func (q Quorum) enabled() bool {
+	return q.MinMembers > 0 || q.Window > 0
+}
+
+// reached reports if a booting Ring has enough members, or has waited long enough, to assign values.
+func (q Quorum) reached(ring Ring) bool {
+	var (
+		members = make(map[string]struct{})
+		oldest  time.Duration
+	)
+	for _, lease := range ring {
+		members[lease.ClientID] = struct{}{}
+		oldest = max(oldest, lease.Age)
+	}
+
+	if q.MinMembers > 0 && len(members) >= q.MinMembers {
+		return true
+	}
+
+	return q.Window > 0 && oldest >= q.Window
+}
+
+// booting reports if no entry in the Ring is Leased yet.
+func (ring Ring) booting() bool {
+	for _, lease := range ring {
+		if lease.Status == Leased {
+			return false
+		}
+	}
+
+	return true
+}
+
+// quorumApprovals approves all entries in a booting Ring at once when the Quorum is reached.
+// The approvals are made by the client with the lowest value in the sorted Ring.
+func quorumApprovals(ring Ring, clientID string, quorum Quorum) []Info {
+	if len(ring) == 0 || ring[0].ClientID != clientID || !quorum.reached(ring) {
+		return nil
+	}
+
+	return append([]Info{}, ring...)
+}
@@ -0,0 +1,141 @@
+package lease
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyuff/dbleases/internal/assert"
+)
+
+func TestQuorum(t *testing.T) {
+	var (
+		booting = func() Ring {
+			return Ring{
+				{ClientID: "my-client", Name: "lease-a", Value: 2, Status: Pending, Age: time.Second},
+				{ClientID: "client-1", Name: "lease-a", Value: 4, Status: Pending, Age: time.Second},
+				{ClientID: "client-2", Name: "lease-a", Value: 6, Status: Pending, Age: time.Second},
+			}
+		}
+	)
+
+	t.Run("should not approve before min members are present", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RingStrategy{}
+			snapshot = Snapshot{
+				ClientID: "my-client",
+				Size:     10,
+				Ring:     booting(),
+				Quorum:   Quorum{MinMembers: 4},
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		assert.DeepEqualSlice(t, []Info{}, got.Approvals)
+		assert.EqualSlice(t, []int{}, got.Values.Values())
+	})
+
+	t.Run("should approve all as lowest client when min members are present", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RingStrategy{}
+			snapshot = Snapshot{
+				ClientID: "my-client",
+				Size:     10,
+				Ring:     booting(),
+				Quorum:   Quorum{MinMembers: 3},
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		assert.DeepEqualSlice(t, []Info(booting()), got.Approvals)
+	})
+
+	t.Run("should leave approvals to the lowest client", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RingStrategy{}
+			snapshot = Snapshot{
+				ClientID: "client-1",
+				Size:     10,
+				Ring:     booting(),
+				Quorum:   Quorum{MinMembers: 3},
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		assert.DeepEqualSlice(t, []Info{}, got.Approvals)
+	})
+
+	t.Run("should approve all when the window has passed", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RingStrategy{}
+			snapshot = Snapshot{
+				ClientID: "my-client",
+				Size:     10,
+				Ring:     booting(),
+				Quorum:   Quorum{MinMembers: 10, Window: time.Second},
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		assert.DeepEqualSlice(t, []Info(booting()), got.Approvals)
+	})
+
+	t.Run("should use normal approvals in a started ring", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RingStrategy{}
+			snapshot = Snapshot{
+				ClientID: "my-client",
+				Size:     10,
+				Ring: Ring{
+					{ClientID: "my-client", Name: "lease-a", Value: 2, Status: Leased},
+					{ClientID: "client-1", Name: "lease-a", Value: 4, Status: Pending},
+				},
+				Quorum: Quorum{MinMembers: 10},
+			}
+			expectApprove = []Info{
+				{ClientID: "client-1", Name: "lease-a", Value: 4, Status: Pending},
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		assert.DeepEqualSlice(t, expectApprove, got.Approvals)
+	})
+
+	t.Run("should approve all with rendezvous when min members are present", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RendezvousStrategy{}
+			snapshot = Snapshot{
+				ClientID: "my-client",
+				Size:     10,
+				Ring:     booting(),
+				Quorum:   Quorum{MinMembers: 3},
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		assert.DeepEqualSlice(t, []Info(booting()), got.Approvals)
+	})
+}
@@ -0,0 +1,29 @@
+package lease
+
+import "time"
+
+// Rebalancing configures how clients move values between them to even out their shares.
+type Rebalancing struct {
+	// Threshold is the difference in values between two clients before they balance.
+	Threshold int
+	// MaxMoves is the most values moved by a client in each Interval. Zero means no limit.
+	MaxMoves int
+	// Interval is the minimum time between balancing by a client.
+	Interval time.Duration
+	// Hold is the minimum age of all entries in the Ring before values are moved again.
+	Hold time.Duration
+}
+
+// DefaultRebalancing moves half the difference at once, when it is more than 5 values.
+var DefaultRebalancing = Rebalancing{
+	Threshold: 5,
+}
+
+// limit the number of values to move.
+func (r Rebalancing) limit(moves int) int {
+	if r.MaxMoves > 0 && moves > r.MaxMoves {
+		return r.MaxMoves
+	}
+
+	return moves
+}
@@ -0,0 +1,250 @@
+package lease
+
+import (
+	"math/bits"
+	"sort"
+
+	"github.com/kyuff/dbleases/internal/hash"
+)
+
+// RendezvousStrategy assigns each value to the member with the highest score of (clientID, value).
+//
+// The entries in the Ring are only used for membership. When a member joins or leaves,
+// only the values it wins or loses move, which is about 1/N of the values.
+//
+// The rules are:
+// - A member is a client with an entry in the Ring
+// - A value is owned by the member with the highest weighted score, if that member is Leased
+// - The values of a Pending member are unassigned until it is approved
+// - A member with MaxPartitions wins no more values, which go to the next member by score
+// - If a Ring only consists of Pending clients, the lowest value client must approve itself.
+// - With a Quorum, the lowest value client approves all clients at once when the Quorum is reached
+// - The Leased client with the lowest value approves all Pending clients, once they are older than a Heartbeat
+// - A client that is not in the Ring requests the lowest free value
+// - Standby entries are not members, and do not take over values
+// - Each zone has an equal share of the values, which is split between the members in the zone by weight
+// - Replicas of a value are held by the next members by score, that are Leased and in other zones
+type RendezvousStrategy struct{}
+
+func (RendezvousStrategy) Name() string {
+	return "rendezvous"
+}
+
+func (RendezvousStrategy) Analyze(snapshot Snapshot) Report {
+	var (
+		ring     = snapshot.Ring
+		report   = Report{Unassigned: snapshot.Size}
+		members  = make(map[string]*rendezvousMember)
+		ids      []string
+		replicas []Span
+		first    *Info
+	)
+	sort.Slice(ring, func(i, j int) bool { return ring[i].Value < ring[j].Value })
+
+	ring = ring.active()
+	if len(ring) == 0 {
+		report.Members = ringMembers(snapshot.Ring, nil)
+		return report
+	}
+
+	for i, entry := range ring {
+		member, ok := members[entry.ClientID]
+		if !ok {
+			member = &rendezvousMember{Client: Client{
+				ID:            entry.ClientID,
+				Weight:        entry.Weight,
+				MaxPartitions: entry.MaxPartitions,
+				Zone:          entry.Zone,
+			}}
+			members[entry.ClientID] = member
+			ids = append(ids, entry.ClientID)
+		}
+
+		if entry.Status == Leased {
+			member.leased = true
+			if first == nil {
+				first = &ring[i]
+			}
+		}
+	}
+
+	switch {
+	case first == nil && snapshot.Quorum.enabled():
+		report.Approvals = quorumApprovals(ring, snapshot.ClientID, snapshot.Quorum)
+	case first == nil:
+		// booting: the lowest value client approves itself
+		if ring[0].ClientID == snapshot.ClientID {
+			report.Approvals = append(report.Approvals, ring[0])
+		}
+	case first.ClientID == snapshot.ClientID:
+		// a Pending entry is approved when all members have read a Ring with it, and left its values
+		for _, entry := range ring {
+			if entry.Status == Pending && entry.Age >= snapshot.Heartbeat {
+				report.Approvals = append(report.Approvals, entry)
+			}
+		}
+	}
+
+	var zones = make(map[string]int)
+	for _, member := range members {
+		zones[member.Zone] += member.weight()
+	}
+	for _, member := range members {
+		member.zoneWeight = zones[member.Zone]
+	}
+
+	// sorted ids make ties between equal scores resolve the same way in all clients
+	sort.Strings(ids)
+	for value := 0; value < snapshot.Size; value++ {
+		var owner *rendezvousMember
+		for _, id := range ids {
+			member := members[id]
+			if member.capped() {
+				continue
+			}
+
+			if owner == nil || member.outranks(owner, value) {
+				owner = member
+			}
+		}
+
+		if owner == nil {
+			// all members are at their MaxPartitions
+			continue
+		}
+
+		owner.count++
+		if owner.leased {
+			owner.Spans = spansAppend(owner.Spans, value)
+			if snapshot.Replicas > 0 && rendezvousReplica(members, ids, owner, value, snapshot) {
+				replicas = spansAppend(replicas, value)
+			}
+		}
+	}
+
+	var owned = make(map[string]Set, len(members))
+	for _, member := range members {
+		owned[member.ID] = SetOf(member.owned()...)
+		report.Unassigned -= owned[member.ID].Len()
+	}
+	report.Members = ringMembers(snapshot.Ring, owned)
+	report.Replicas = SetOf(replicas...)
+
+	report.Values = owned[snapshot.ClientID]
+
+	if _, ok := members[snapshot.ClientID]; !ok && first != nil && !snapshot.Standby {
+		report.Balance = freeValueRequest(ring, snapshot)
+	}
+
+	return report
+}
+
+type rendezvousMember struct {
+	Client
+	leased bool
+	// count of values the member has won, including the values of a Pending member
+	count int
+}
+
+// outranks reports if the member has a higher score for the value than other, as in weighted rendezvous hashing.
+//
+// A member with the share w and the hash u in (0, 1) scores w / -ln(u). Members with equal shares are ranked
+// by their hash, and other members by a fixed point -log2(u), so the ranking only uses integers and is the
+// same on all architectures.
+func (m *rendezvousMember) outranks(other *rendezvousMember, value int) bool {
+	var (
+		mScore, oScore = hash.Score(m.ID, value), hash.Score(other.ID, value)
+		mNum, mDen     = m.share()
+		oNum, oDen     = other.share()
+	)
+	if mNum*oDen == oNum*mDen {
+		return mScore > oScore
+	}
+
+	// mNum/mDen / mLog > oNum/oDen / oLog, with both sides multiplied by the denominators
+	mHi, mLo := bits.Mul64(uint64(mNum*oDen), negLog2(oScore))
+	oHi, oLo := bits.Mul64(uint64(oNum*mDen), negLog2(mScore))
+	return mHi > oHi || mHi == oHi && mLo > oLo
+}
+
+// negLog2 is -log2(u) with 32 fractional bits, where u is the score as a fraction of 2^64.
+func negLog2(score uint64) uint64 {
+	var (
+		x        = score | 1
+		zeros    = bits.LeadingZeros64(x)
+		mantissa = x << zeros // in [1, 2) with the point after the highest bit
+		fraction uint64
+	)
+	for i := 0; i < 32; i++ {
+		hi, lo := bits.Mul64(mantissa, mantissa)
+		fraction <<= 1
+		if hi >= 1<<63 {
+			// the square is at least 2, so the next bit of the logarithm is set
+			fraction |= 1
+			mantissa = hi
+		} else {
+			mantissa = hi<<1 | lo>>63
+		}
+	}
+
+	// log2(x) = 63 - zeros + fraction, and -log2(u) = 64 - log2(x)
+	return uint64(zeros+1)<<32 - fraction
+}
+
+// capped reports if the member has won as many values as its MaxPartitions.
+func (m *rendezvousMember) capped() bool {
+	return m.MaxPartitions > 0 && m.count >= m.MaxPartitions
+}
+
+// rendezvousReplica reports if the client of the snapshot holds a replica of a value owned by owner.
+// The replicas are the next Leased members by score in zones without the owner or another replica.
+func rendezvousReplica(members map[string]*rendezvousMember, ids []string, owner *rendezvousMember, value int, snapshot Snapshot) bool {
+	var ranked []*rendezvousMember
+	for _, id := range ids {
+		if member := members[id]; member.leased && member != owner {
+			ranked = append(ranked, member)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].outranks(ranked[j], value) })
+
+	var zones = map[string]bool{owner.Zone: true}
+	for _, member := range ranked {
+		if len(zones) > snapshot.Replicas {
+			break
+		}
+		if zones[member.Zone] {
+			continue
+		}
+
+		zones[member.Zone] = true
+		if member.ID == snapshot.ClientID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// freeValueRequest asks for the lowest value without an entry in the sorted Ring.
+func freeValueRequest(ring Ring, snapshot Snapshot) *Request {
+	var value = 0
+	for _, entry := range ring {
+		if entry.Value > value {
+			break
+		}
+		if entry.Value == value {
+			value++
+		}
+	}
+
+	if value >= snapshot.Size {
+		return nil
+	}
+
+	return &Request{
+		ClientID:  snapshot.ClientID,
+		LeaseName: ring[0].Name,
+		Value:     value,
+		Status:    Pending,
+	}
+}
@@ -0,0 +1,335 @@
+package lease
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyuff/dbleases/internal/assert"
+	"github.com/kyuff/dbleases/internal/hash"
+)
+
+func TestRendezvousStrategy(t *testing.T) {
+	var (
+		sut     = RendezvousStrategy{}
+		analyze = func(clientID string, size int, ring Ring) Report {
+			return sut.Analyze(Snapshot{ClientID: clientID, Size: size, Ring: append(Ring{}, ring...)})
+		}
+	)
+
+	t.Run("should take all values as only lease", func(t *testing.T) {
+		// arrange
+		var (
+			ring = Ring{
+				{ClientID: "my-client", Name: "lease-a", Value: 1, Status: Leased},
+			}
+		)
+
+		// act
+		got := analyze("my-client", 4, ring)
+
+		// assert
+		assert.EqualSlice(t, []int{0, 1, 2, 3}, got.Values.Values())
+		assert.Equal(t, 0, got.Unassigned)
+	})
+
+	t.Run("should approve itself as first pending", func(t *testing.T) {
+		// arrange
+		var (
+			ring = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 3, Status: Pending},
+				{ClientID: "my-client", Name: "lease-a", Value: 1, Status: Pending},
+			}
+			expectApprove = []Info{
+				{ClientID: "my-client", Name: "lease-a", Value: 1, Status: Pending},
+			}
+		)
+
+		// act
+		got := analyze("my-client", 4, ring)
+
+		// assert
+		assert.EqualSlice(t, []int{}, got.Values.Values())
+		assert.DeepEqualSlice(t, expectApprove, got.Approvals)
+	})
+
+	t.Run("should approve pending clients as first leased", func(t *testing.T) {
+		// arrange
+		var (
+			ring = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Pending},
+				{ClientID: "my-client", Name: "lease-a", Value: 1, Status: Leased},
+				{ClientID: "client-2", Name: "lease-a", Value: 2, Status: Pending},
+			}
+			expectApprove = []Info{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Pending},
+				{ClientID: "client-2", Name: "lease-a", Value: 2, Status: Pending},
+			}
+		)
+
+		// act
+		got := analyze("my-client", 100, ring)
+
+		// assert
+		assert.DeepEqualSlice(t, expectApprove, got.Approvals)
+		assert.Equal(t, 100-len(got.Values.Values()), got.Unassigned)
+	})
+
+	t.Run("should not approve a pending client younger than a heartbeat", func(t *testing.T) {
+		// arrange
+		var (
+			ring = Ring{
+				{ClientID: "my-client", Name: "lease-a", Value: 0, Status: Leased, Age: time.Minute},
+				{ClientID: "client-1", Name: "lease-a", Value: 1, Status: Pending, Age: time.Second},
+				{ClientID: "client-2", Name: "lease-a", Value: 2, Status: Pending, Age: 5 * time.Second},
+			}
+			expectApprove = []Info{
+				{ClientID: "client-2", Name: "lease-a", Value: 2, Status: Pending, Age: 5 * time.Second},
+			}
+		)
+
+		// act
+		got := sut.Analyze(Snapshot{ClientID: "my-client", Size: 100, Ring: ring, Heartbeat: 5 * time.Second})
+
+		// assert
+		assert.DeepEqualSlice(t, expectApprove, got.Approvals)
+	})
+
+	t.Run("should leave the values of a pending client before it is approved", func(t *testing.T) {
+		// arrange
+		var (
+			size    = 100
+			pending = Ring{
+				{ClientID: "my-client", Name: "lease-a", Value: 0, Status: Leased},
+				{ClientID: "client-1", Name: "lease-a", Value: 1, Status: Leased},
+				{ClientID: "client-2", Name: "lease-a", Value: 2, Status: Pending},
+			}
+			approved = Ring{
+				{ClientID: "my-client", Name: "lease-a", Value: 0, Status: Leased},
+				{ClientID: "client-1", Name: "lease-a", Value: 1, Status: Leased},
+				{ClientID: "client-2", Name: "lease-a", Value: 2, Status: Leased},
+			}
+			joined = analyze("client-2", size, approved).Values
+		)
+
+		for _, clientID := range []string{"my-client", "client-1"} {
+			// act
+			got := analyze(clientID, size, pending)
+
+			// assert
+			for _, value := range joined.Values() {
+				if got.Values.Contains(value) {
+					t.Errorf("value %d of the pending client owned by %s", value, clientID)
+				}
+			}
+		}
+	})
+
+	t.Run("should split all values between leased clients", func(t *testing.T) {
+		// arrange
+		var (
+			size = 100
+			ring = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased},
+				{ClientID: "client-2", Name: "lease-a", Value: 1, Status: Leased},
+				{ClientID: "client-3", Name: "lease-a", Value: 2, Status: Leased},
+			}
+			owners = make(map[int]string)
+		)
+
+		// act
+		for _, clientID := range []string{"client-1", "client-2", "client-3"} {
+			got := analyze(clientID, size, ring)
+			assert.Equal(t, 0, got.Unassigned)
+			for _, value := range got.Values.Values() {
+				if owner, ok := owners[value]; ok {
+					t.Errorf("value %d owned by %s and %s", value, owner, clientID)
+				}
+				owners[value] = clientID
+			}
+		}
+
+		// assert
+		assert.Equal(t, size, len(owners))
+	})
+
+	t.Run("should only move the values of a client that leaves", func(t *testing.T) {
+		// arrange
+		var (
+			size   = 100
+			before = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased},
+				{ClientID: "my-client", Name: "lease-a", Value: 1, Status: Leased},
+				{ClientID: "client-2", Name: "lease-a", Value: 2, Status: Leased},
+			}
+			after = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased},
+				{ClientID: "my-client", Name: "lease-a", Value: 1, Status: Leased},
+			}
+			kept = analyze("my-client", size, before).Values.Values()
+		)
+
+		// act
+		got := analyze("my-client", size, after)
+
+		// assert
+		for _, value := range kept {
+			assert.OneOf(t, got.Values.Values(), value)
+		}
+	})
+
+	t.Run("should give a heavier client more values", func(t *testing.T) {
+		// arrange
+		var (
+			size = 1000
+			ring = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased, Weight: 1},
+				{ClientID: "my-client", Name: "lease-a", Value: 1, Status: Leased, Weight: 3},
+			}
+		)
+
+		// act
+		got := analyze("my-client", size, ring)
+
+		// assert
+		if len(got.Values.Values()) < size*2/3 {
+			t.Errorf("expected at least %d values, got %d", size*2/3, len(got.Values.Values()))
+		}
+	})
+
+	t.Run("should give the values beyond max partitions to the next client", func(t *testing.T) {
+		// arrange
+		var (
+			size = 100
+			ring = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased, MaxPartitions: 10},
+				{ClientID: "my-client", Name: "lease-a", Value: 1, Status: Leased},
+			}
+		)
+
+		// act
+		capped := analyze("client-1", size, ring)
+		got := analyze("my-client", size, ring)
+
+		// assert
+		assert.Equal(t, 10, capped.Values.Len())
+		assert.Equal(t, 90, got.Values.Len())
+		assert.Equal(t, 0, got.Unassigned)
+		for _, value := range capped.Values.Values() {
+			assert.Equal(t, false, got.Values.Contains(value))
+		}
+	})
+
+	t.Run("should request the lowest free value to enter the ring", func(t *testing.T) {
+		// arrange
+		var (
+			ring = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased},
+				{ClientID: "client-2", Name: "lease-a", Value: 1, Status: Leased},
+				{ClientID: "client-3", Name: "lease-a", Value: 3, Status: Leased},
+			}
+			expectBalance = Request{
+				ClientID: "my-client", LeaseName: "lease-a", Value: 2, Status: Pending,
+			}
+		)
+
+		// act
+		got := analyze("my-client", 10, ring)
+
+		// assert
+		if assert.NotNil(t, got.Balance) {
+			assert.Equal(t, expectBalance, *got.Balance)
+		}
+	})
+
+	t.Run("should not request to enter a booting ring", func(t *testing.T) {
+		// arrange
+		var (
+			ring = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Pending},
+			}
+		)
+
+		// act
+		got := analyze("my-client", 10, ring)
+
+		// assert
+		assert.Nil(t, got.Balance)
+	})
+}
+
+func TestNegLog2(t *testing.T) {
+	t.Run("should be exact for powers of two", func(t *testing.T) {
+		// act
+		half := negLog2(1 << 63)
+		quarter := negLog2(1 << 62)
+
+		// assert
+		assert.Equal(t, uint64(1)<<32, half)
+		assert.Equal(t, uint64(2)<<32, quarter)
+	})
+
+	t.Run("should have 32 fractional bits", func(t *testing.T) {
+		// arrange
+		var (
+			// -log2(0.75) * 2^32
+			expected = uint64(1782572486)
+		)
+
+		// act
+		got := negLog2(3 << 62)
+
+		// assert
+		if got < expected || got > expected+1 {
+			t.Errorf("expected %d, got %d", expected, got)
+		}
+	})
+
+	t.Run("should decrease with the score", func(t *testing.T) {
+		// act
+		low := negLog2(1)
+		high := negLog2(1<<64 - 1)
+
+		// assert
+		assert.Equal(t, uint64(64)<<32, low)
+		assert.Equal(t, true, high < 1<<32)
+	})
+}
+
+func TestRendezvousOutranks(t *testing.T) {
+	t.Run("should rank by score when shares are equal", func(t *testing.T) {
+		// arrange
+		var (
+			a = &rendezvousMember{Client: Client{ID: "client-1"}}
+			b = &rendezvousMember{Client: Client{ID: "client-2"}}
+		)
+
+		for value := 0; value < 100; value++ {
+			// act
+			got := a.outranks(b, value)
+
+			// assert
+			assert.Equal(t, hash.Score(a.ID, value) > hash.Score(b.ID, value), got)
+		}
+	})
+
+	t.Run("should rank a heavier member higher", func(t *testing.T) {
+		// arrange
+		var (
+			light = &rendezvousMember{Client: Client{ID: "client-1", Weight: 1}}
+			heavy = &rendezvousMember{Client: Client{ID: "client-2", Weight: 3}}
+			wins  int
+		)
+
+		// act
+		for value := 0; value < 1000; value++ {
+			if heavy.outranks(light, value) {
+				wins++
+			}
+		}
+
+		// assert
+		if wins < 700 || wins > 800 {
+			t.Errorf("expected about 750 wins, got %d", wins)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package lease
+
+// ringRange is the spans from a Leased entry up to the next entry in the Ring.
+type ringRange struct {
+	lease Info
+	spans []Span
+}
+
+// replicas are the values the client holds a replica of. The replicas of a range are
+// held by the next clients in the Ring, that are in a zone without the owner or another replica.
+func replicas(ranges []ringRange, clientID string, count int) Set {
+	var (
+		result []Span
+		taken  = make(map[string]int)
+	)
+	for i, r := range ranges {
+		var (
+			zones   = map[string]bool{r.lease.Zone: true}
+			clients = map[string]bool{r.lease.ClientID: true}
+			found   = 0
+			spans   = r.spans
+		)
+
+		// only values owned within the MaxPartitions of the owner are replicated
+		if r.lease.MaxPartitions > 0 {
+			spans = spansTake(spans, max(0, r.lease.MaxPartitions-taken[r.lease.ClientID]))
+		}
+		taken[r.lease.ClientID] += spansLen(r.spans)
+
+		for k := 1; k < len(ranges) && found < count; k++ {
+			next := ranges[(i+k)%len(ranges)].lease
+			if zones[next.Zone] || clients[next.ClientID] {
+				continue
+			}
+
+			zones[next.Zone] = true
+			clients[next.ClientID] = true
+			found++
+
+			if next.ClientID == clientID {
+				result = append(result, spans...)
+			}
+		}
+	}
+
+	return SetOf(result...)
+}
@@ -0,0 +1,7 @@
+package lease
+
+// Resource is a named key in a lease over a dynamic set of resources.
+type Resource struct {
+	Name string
+	Key  string
+}
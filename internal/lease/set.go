@@ -0,0 +1,283 @@
+package lease
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Span is the values from and including From, up to but not including To.
+type Span struct {
+	From int
+	To   int
+}
+
+func (s Span) len() int {
+	return s.To - s.From
+}
+
+// Set is an immutable set of values, stored as sorted spans of consecutive values.
+//
+// The zero value is an empty Set.
+type Set struct {
+	spans []Span
+	len   int
+}
+
+// NewSet of the values. The values need not be sorted, and duplicates are ignored.
+func NewSet(values ...int) Set {
+	var spans = make([]Span, 0, len(values))
+	for _, value := range values {
+		spans = append(spans, Span{From: value, To: value + 1})
+	}
+
+	return SetOf(spans...)
+}
+
+// SetOf the values in the spans. The spans need not be sorted, and may overlap.
+func SetOf(spans ...Span) Set {
+	var sorted = make([]Span, 0, len(spans))
+	for _, span := range spans {
+		if span.len() > 0 {
+			sorted = append(sorted, span)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].From < sorted[j].From })
+
+	var set Set
+	for _, span := range sorted {
+		last := len(set.spans) - 1
+		if last >= 0 && span.From <= set.spans[last].To {
+			set.spans[last].To = max(set.spans[last].To, span.To)
+			continue
+		}
+
+		set.spans = append(set.spans, span)
+	}
+
+	for _, span := range set.spans {
+		set.len += span.len()
+	}
+
+	return set
+}
+
+// Len is the number of values in the Set.
+func (s Set) Len() int {
+	return s.len
+}
+
+// Spans of the Set in increasing order. The slice is a copy.
+//
+// Spans is a function and not a method, so the Span type stays out of the public
+// PartitionSet, which is an alias of Set.
+func Spans(s Set) []Span {
+	return append([]Span(nil), s.spans...)
+}
+
+// Contains reports if the value is in the Set.
+func (s Set) Contains(value int) bool {
+	i := sort.Search(len(s.spans), func(i int) bool { return s.spans[i].To > value })
+	return i < len(s.spans) && s.spans[i].From <= value
+}
+
+// Equal reports if the Sets have the same values.
+func (s Set) Equal(other Set) bool {
+	if s.len != other.len || len(s.spans) != len(other.spans) {
+		return false
+	}
+
+	for i := range s.spans {
+		if s.spans[i] != other.spans[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Union of the values in s and other.
+func (s Set) Union(other Set) Set {
+	return SetOf(append(append([]Span{}, s.spans...), other.spans...)...)
+}
+
+// Diff is the values in s that are not in other.
+func (s Set) Diff(other Set) Set {
+	var (
+		spans []Span
+		j     = 0
+	)
+	for _, span := range s.spans {
+		for j < len(other.spans) && other.spans[j].To <= span.From {
+			j++
+		}
+
+		from := span.From
+		for k := j; k < len(other.spans) && other.spans[k].From < span.To; k++ {
+			if other.spans[k].From > from {
+				spans = append(spans, Span{From: from, To: other.spans[k].From})
+			}
+			from = max(from, other.spans[k].To)
+		}
+
+		if from < span.To {
+			spans = append(spans, Span{From: from, To: span.To})
+		}
+	}
+
+	return SetOf(spans...)
+}
+
+// Each calls yield with the values in increasing order, until yield returns false.
+func (s Set) Each(yield func(value int) bool) {
+	for _, span := range s.spans {
+		for value := span.From; value < span.To; value++ {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Values in increasing order.
+func (s Set) Values() []int {
+	var values = make([]int, 0, s.len)
+	s.Each(func(value int) bool {
+		values = append(values, value)
+		return true
+	})
+
+	return values
+}
+
+// String in the format "n/ranges", where n is the number of values and ranges
+// are the values as inclusive ranges, such as "5/0-3,7". An empty Set is "0/-".
+func (s Set) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(strconv.Itoa(s.len))
+	buf.WriteRune('/')
+
+	if len(s.spans) == 0 {
+		buf.WriteRune('-')
+		return buf.String()
+	}
+
+	for i, span := range s.spans {
+		if i > 0 {
+			buf.WriteRune(',')
+		}
+		buf.WriteString(strconv.Itoa(span.From))
+		if span.len() > 1 {
+			buf.WriteRune('-')
+			buf.WriteString(strconv.Itoa(span.To - 1))
+		}
+	}
+
+	return buf.String()
+}
+
+// ParseSet from the format of Set.String.
+func ParseSet(s string) (Set, error) {
+	count, list, ok := strings.Cut(s, "/")
+	if !ok {
+		return Set{}, fmt.Errorf("[dbleases] invalid set %q: missing count", s)
+	}
+
+	n, err := strconv.Atoi(count)
+	if err != nil {
+		return Set{}, fmt.Errorf("[dbleases] invalid set %q: %w", s, err)
+	}
+
+	var spans []Span
+	if list != "-" {
+		for _, part := range strings.Split(list, ",") {
+			span, err := parseSpan(part)
+			if err != nil {
+				return Set{}, fmt.Errorf("[dbleases] invalid set %q: %w", s, err)
+			}
+			spans = append(spans, span)
+		}
+	}
+
+	set := SetOf(spans...)
+	if set.len != n {
+		return Set{}, fmt.Errorf("[dbleases] invalid set %q: count is %d, got %d values", s, n, set.len)
+	}
+
+	return set, nil
+}
+
+func parseSpan(s string) (Span, error) {
+	from, to, isRange := strings.Cut(s, "-")
+	f, err := strconv.Atoi(from)
+	if err != nil {
+		return Span{}, err
+	}
+
+	if !isRange {
+		return Span{From: f, To: f + 1}, nil
+	}
+
+	t, err := strconv.Atoi(to)
+	if err != nil {
+		return Span{}, err
+	}
+
+	if t < f {
+		return Span{}, fmt.Errorf("range %d-%d is reversed", f, t)
+	}
+
+	return Span{From: f, To: t + 1}, nil
+}
+
+// spansLen is the number of values in the spans.
+func spansLen(spans []Span) int {
+	var n int
+	for _, span := range spans {
+		n += span.len()
+	}
+
+	return n
+}
+
+// spansTake is the first n values of the spans, in the order of the spans.
+func spansTake(spans []Span, n int) []Span {
+	var taken []Span
+	for _, span := range spans {
+		if n <= 0 {
+			break
+		}
+		if span.len() > n {
+			span.To = span.From + n
+		}
+		taken = append(taken, span)
+		n -= span.len()
+	}
+
+	return taken
+}
+
+// spansAt is the i'th value of the spans, in the order of the spans.
+func spansAt(spans []Span, i int) int {
+	for _, span := range spans {
+		if i < span.len() {
+			return span.From + i
+		}
+		i -= span.len()
+	}
+
+	panic(fmt.Sprintf("index %d out of spans", i))
+}
+
+// spansAppend adds a value after the spans, extending the last span if the value is next to it.
+func spansAppend(spans []Span, value int) []Span {
+	last := len(spans) - 1
+	if last >= 0 && spans[last].To == value {
+		spans[last].To++
+		return spans
+	}
+
+	return append(spans, Span{From: value, To: value + 1})
+}
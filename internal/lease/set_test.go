@@ -0,0 +1,65 @@
+package lease
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kyuff/dbleases/internal/assert"
+)
+
+func TestSet(t *testing.T) {
+	t.Run("should merge overlapping and adjacent spans", func(t *testing.T) {
+		// act
+		got := SetOf(Span{From: 5, To: 8}, Span{From: 0, To: 3}, Span{From: 3, To: 4}, Span{From: 6, To: 10})
+
+		// assert
+		assert.EqualSlice(t, []Span{{From: 0, To: 4}, {From: 5, To: 10}}, Spans(got))
+		assert.Equal(t, 9, got.Len())
+	})
+
+	t.Run("should take the first values in the order of the spans", func(t *testing.T) {
+		// arrange
+		var (
+			spans = []Span{{From: 8, To: 10}, {From: 0, To: 3}}
+		)
+
+		// act
+		got := spansTake(spans, 3)
+
+		// assert
+		assert.EqualSlice(t, []Span{{From: 8, To: 10}, {From: 0, To: 1}}, got)
+		assert.Equal(t, 3, spansLen(got))
+		assert.Equal(t, 9, spansAt(spans, 1))
+		assert.Equal(t, 2, spansAt(spans, 4))
+	})
+
+	t.Run("should extend the last span when appending the next value", func(t *testing.T) {
+		// act
+		got := spansAppend(spansAppend(spansAppend(nil, 1), 2), 4)
+
+		// assert
+		assert.EqualSlice(t, []Span{{From: 1, To: 3}, {From: 4, To: 5}}, got)
+	})
+}
+
+func BenchmarkAnalyzeRing(b *testing.B) {
+	for _, size := range []int{1 << 10, 1 << 16, 1 << 20} {
+		b.Run(fmt.Sprintf("size %d", size), func(b *testing.B) {
+			var ring Ring
+			for i := 0; i < 10; i++ {
+				ring = append(ring, Info{
+					ClientID: fmt.Sprintf("client-%d", i),
+					Name:     "lease-a",
+					Value:    i * size / 10,
+					Status:   Leased,
+				})
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = ring.Analyze("client-1", size)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package lease
+
+// active entries in the Ring, leaving out Standby entries.
+func (ring Ring) active() Ring {
+	var active = make(Ring, 0, len(ring))
+	for _, lease := range ring {
+		if lease.Status != Standby {
+			active = append(active, lease)
+		}
+	}
+
+	return active
+}
+
+// takeovers requests the Leased entries of clients that have left the Ring since the Previous Snapshot.
+// The Pending requests are approved by the previous Leased entry in the Ring, which gives the Standby
+// client the same values as the client that left.
+func takeovers(snapshot Snapshot) []Request {
+	var (
+		present  = make(map[string]bool)
+		occupied = make(map[int]bool)
+		requests []Request
+	)
+	for _, lease := range snapshot.Ring {
+		present[lease.ClientID] = true
+		occupied[lease.Value] = true
+	}
+
+	for _, lease := range snapshot.Previous {
+		if lease.Status != Leased || lease.ClientID == snapshot.ClientID {
+			continue
+		}
+
+		if present[lease.ClientID] || occupied[lease.Value] {
+			continue
+		}
+
+		requests = append(requests, Request{
+			ClientID:  snapshot.ClientID,
+			LeaseName: lease.Name,
+			Value:     lease.Value,
+			Status:    Pending,
+		})
+	}
+
+	return requests
+}
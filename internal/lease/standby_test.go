@@ -0,0 +1,135 @@
+package lease
+
+import (
+	"testing"
+
+	"github.com/kyuff/dbleases/internal/assert"
+)
+
+func TestStandby(t *testing.T) {
+	t.Run("should give no values to a standby client", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RingStrategy{}
+			snapshot = Snapshot{
+				ClientID: "standby",
+				Size:     5,
+				Standby:  true,
+				Ring: Ring{
+					{ClientID: "standby", Name: "lease-a", Value: -5, Status: Standby},
+					{ClientID: "client-1", Name: "lease-a", Value: 2, Status: Leased},
+					{ClientID: "client-2", Name: "lease-a", Value: 4, Status: Leased},
+				},
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		assert.EqualSlice(t, []int{}, got.Values.Values())
+		assert.Nil(t, got.Balance)
+		assert.EqualSlice(t, []Request{}, got.Takeovers)
+	})
+
+	t.Run("should ignore standby entries in the ring", func(t *testing.T) {
+		// arrange
+		var (
+			sut = Ring{
+				{ClientID: "standby", Name: "lease-a", Value: -5, Status: Standby},
+				{ClientID: "my-client", Name: "lease-a", Value: 2, Status: Leased},
+				{ClientID: "client-2", Name: "lease-a", Value: 4, Status: Leased},
+			}
+		)
+
+		// act
+		got := sut.Analyze("my-client", 5)
+
+		// assert
+		assert.EqualSlice(t, []int{2, 3}, got.Values.Values())
+		assert.Equal(t, 0, got.Unassigned)
+	})
+
+	t.Run("should take over the entries of a client that left", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RingStrategy{}
+			snapshot = Snapshot{
+				ClientID: "standby",
+				Size:     10,
+				Standby:  true,
+				Previous: Ring{
+					{ClientID: "standby", Name: "lease-a", Value: -5, Status: Standby},
+					{ClientID: "client-1", Name: "lease-a", Value: 2, Status: Leased},
+					{ClientID: "client-2", Name: "lease-a", Value: 4, Status: Leased},
+					{ClientID: "client-2", Name: "lease-a", Value: 8, Status: Leased},
+				},
+				Ring: Ring{
+					{ClientID: "standby", Name: "lease-a", Value: -5, Status: Standby},
+					{ClientID: "client-1", Name: "lease-a", Value: 2, Status: Leased},
+				},
+			}
+			expectTakeovers = []Request{
+				{ClientID: "standby", LeaseName: "lease-a", Value: 4, Status: Pending},
+				{ClientID: "standby", LeaseName: "lease-a", Value: 8, Status: Pending},
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		assert.EqualSlice(t, expectTakeovers, got.Takeovers)
+	})
+
+	t.Run("should not take over from a client that is still in the ring", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RingStrategy{}
+			snapshot = Snapshot{
+				ClientID: "standby",
+				Size:     10,
+				Standby:  true,
+				Previous: Ring{
+					{ClientID: "client-1", Name: "lease-a", Value: 2, Status: Leased},
+					{ClientID: "client-2", Name: "lease-a", Value: 4, Status: Leased},
+				},
+				Ring: Ring{
+					{ClientID: "standby", Name: "lease-a", Value: -5, Status: Standby},
+					{ClientID: "client-1", Name: "lease-a", Value: 2, Status: Leased},
+					{ClientID: "client-2", Name: "lease-a", Value: 6, Status: Leased},
+				},
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		assert.EqualSlice(t, []Request{}, got.Takeovers)
+	})
+
+	t.Run("should have taken over values once approved", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RingStrategy{}
+			snapshot = Snapshot{
+				ClientID: "standby",
+				Size:     10,
+				Standby:  true,
+				Ring: Ring{
+					{ClientID: "standby", Name: "lease-a", Value: -5, Status: Standby},
+					{ClientID: "client-1", Name: "lease-a", Value: 2, Status: Leased},
+					{ClientID: "standby", Name: "lease-a", Value: 4, Status: Leased},
+				},
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		assert.EqualSlice(t, []int{0, 1, 4, 5, 6, 7, 8, 9}, got.Values.Values())
+		assert.Nil(t, got.Balance)
+	})
+}
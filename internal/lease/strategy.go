@@ -0,0 +1,42 @@
+package lease
+
+import "time"
+
+// Snapshot of a Ring as seen by a client at a heartbeat.
+type Snapshot struct {
+	ClientID    string
+	Size        int
+	Ring        Ring
+	Rebalancing Rebalancing
+	Quorum      Quorum
+	// Standby is set if the client is in the Ring to take over from clients that leave
+	Standby bool
+	// Previous is the Ring from the previous Snapshot of the client
+	Previous Ring
+	// Replicas is the number of replicas of each value, held by clients in other zones
+	Replicas int
+	// Heartbeat is the interval in which the clients of the lease read the Ring
+	Heartbeat time.Duration
+}
+
+// Strategy turns a Snapshot into a Report for the client.
+//
+// A Strategy must be deterministic, as all clients of a lease analyze the same
+// Ring independently and must agree on who owns which values.
+type Strategy interface {
+	// Name identifies the Strategy. It is stored with the lease, so all clients use the same Strategy.
+	Name() string
+	// Analyze the Snapshot to Report the values, approvals and balancing for the client.
+	Analyze(snapshot Snapshot) Report
+}
+
+// RingStrategy assigns values by ranges in the Ring. See Ring.Analyze for the rules.
+type RingStrategy struct{}
+
+func (RingStrategy) Name() string {
+	return "ring"
+}
+
+func (RingStrategy) Analyze(snapshot Snapshot) Report {
+	return analyzeRing(snapshot)
+}
@@ -0,0 +1,132 @@
+package lease
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyuff/dbleases/internal/assert"
+)
+
+func TestRingStrategy(t *testing.T) {
+	t.Run("should analyze the ring of the snapshot", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RingStrategy{}
+			snapshot = Snapshot{
+				ClientID: "my-client",
+				Size:     5,
+				Ring: Ring{
+					{ClientID: "my-client", Name: "lease-a", Value: 2, Status: Leased},
+					{ClientID: "client-1", Name: "lease-a", Value: 4, Status: Leased},
+				},
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		assert.Equal(t, "ring", sut.Name())
+		assert.EqualSlice(t, []int{2, 3}, got.Values.Values())
+	})
+
+	t.Run("should limit the values moved", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RingStrategy{}
+			snapshot = Snapshot{
+				ClientID:    "my-client",
+				Size:        100,
+				Rebalancing: Rebalancing{Threshold: 5, MaxMoves: 10},
+				Ring: Ring{
+					{ClientID: "client-1", Name: "lease-a", Value: 5, Status: Leased},
+					{ClientID: "my-client", Name: "lease-a", Value: 13, Status: Leased},
+					{ClientID: "client-2", Name: "lease-a", Value: 15, Status: Leased},
+				},
+			}
+			expectBalance = Request{
+				ClientID: "my-client", LeaseName: "lease-a", Value: 90, Status: Pending,
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		if assert.NotNil(t, got.Balance) {
+			assert.Equal(t, expectBalance, *got.Balance)
+		}
+	})
+
+	t.Run("should not balance below the threshold", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RingStrategy{}
+			snapshot = Snapshot{
+				ClientID:    "my-client",
+				Size:        100,
+				Rebalancing: Rebalancing{Threshold: 100},
+				Ring: Ring{
+					{ClientID: "client-1", Name: "lease-a", Value: 5, Status: Leased},
+					{ClientID: "my-client", Name: "lease-a", Value: 13, Status: Leased},
+					{ClientID: "client-2", Name: "lease-a", Value: 15, Status: Leased},
+				},
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		assert.Nil(t, got.Balance)
+	})
+
+	t.Run("should hold balancing while the ring has young entries", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RingStrategy{}
+			snapshot = Snapshot{
+				ClientID:    "my-client",
+				Size:        100,
+				Rebalancing: Rebalancing{Threshold: 5, Hold: time.Minute},
+				Ring: Ring{
+					{ClientID: "client-1", Name: "lease-a", Value: 5, Status: Leased, Age: time.Hour},
+					{ClientID: "my-client", Name: "lease-a", Value: 13, Status: Leased, Age: time.Hour},
+					{ClientID: "client-2", Name: "lease-a", Value: 15, Status: Leased, Age: time.Second},
+				},
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		assert.Nil(t, got.Balance)
+	})
+
+	t.Run("should enter the ring while holding", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RingStrategy{}
+			snapshot = Snapshot{
+				ClientID:    "my-client",
+				Size:        10,
+				Rebalancing: Rebalancing{Threshold: 5, Hold: time.Minute},
+				Ring: Ring{
+					{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased, Age: time.Second},
+				},
+			}
+			expectBalance = Request{
+				ClientID: "my-client", LeaseName: "lease-a", Value: 9, Status: Pending,
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		if assert.NotNil(t, got.Balance) {
+			assert.Equal(t, expectBalance, *got.Balance)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package lease
+
+import (
+	"testing"
+
+	"github.com/kyuff/dbleases/internal/assert"
+)
+
+func TestZones(t *testing.T) {
+	t.Run("should balance zones to an equal share", func(t *testing.T) {
+		// arrange
+		var (
+			sut = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased, Zone: "zone-a"},
+				{ClientID: "client-2", Name: "lease-a", Value: 10, Status: Leased, Zone: "zone-a"},
+				{ClientID: "my-client", Name: "lease-a", Value: 15, Status: Leased, Zone: "zone-b"},
+			}
+			expectBalance = Request{
+				ClientID: "my-client", LeaseName: "lease-a", Value: 5, Status: Pending,
+			}
+		)
+
+		// act
+		got := sut.Analyze("my-client", 20)
+
+		// assert
+		if assert.NotNil(t, got.Balance) {
+			assert.Equal(t, expectBalance, *got.Balance)
+		}
+	})
+
+	t.Run("should not balance the same ring without zones", func(t *testing.T) {
+		// arrange
+		var (
+			sut = Ring{
+				{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased},
+				{ClientID: "client-2", Name: "lease-a", Value: 10, Status: Leased},
+				{ClientID: "my-client", Name: "lease-a", Value: 15, Status: Leased},
+			}
+		)
+
+		// act
+		got := sut.Analyze("my-client", 20)
+
+		// assert
+		assert.Nil(t, got.Balance)
+	})
+
+	t.Run("should hold replicas of the next clients in other zones", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RingStrategy{}
+			snapshot = Snapshot{
+				ClientID: "my-client",
+				Size:     12,
+				Replicas: 1,
+				Ring: Ring{
+					{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased, Zone: "zone-a"},
+					{ClientID: "client-2", Name: "lease-a", Value: 4, Status: Leased, Zone: "zone-a"},
+					{ClientID: "my-client", Name: "lease-a", Value: 8, Status: Leased, Zone: "zone-b"},
+				},
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		assert.EqualSlice(t, []int{8, 9, 10, 11}, got.Values.Values())
+		assert.EqualSlice(t, []int{0, 1, 2, 3, 4, 5, 6, 7}, got.Replicas.Values())
+	})
+
+	t.Run("should not hold replicas in the same zone", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RingStrategy{}
+			snapshot = Snapshot{
+				ClientID: "my-client",
+				Size:     12,
+				Replicas: 1,
+				Ring: Ring{
+					{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased, Zone: "zone-a"},
+					{ClientID: "my-client", Name: "lease-a", Value: 8, Status: Leased, Zone: "zone-a"},
+				},
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		assert.EqualSlice(t, []int{}, got.Replicas.Values())
+	})
+
+	t.Run("should give a zone an equal share with rendezvous", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RendezvousStrategy{}
+			size     = 1000
+			snapshot = Snapshot{
+				ClientID: "my-client",
+				Size:     size,
+				Ring: Ring{
+					{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased, Zone: "zone-a"},
+					{ClientID: "client-2", Name: "lease-a", Value: 1, Status: Leased, Zone: "zone-a"},
+					{ClientID: "client-3", Name: "lease-a", Value: 2, Status: Leased, Zone: "zone-a"},
+					{ClientID: "my-client", Name: "lease-a", Value: 3, Status: Leased, Zone: "zone-b"},
+				},
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		if len(got.Values.Values()) < size*2/5 {
+			t.Errorf("expected at least %d values, got %d", size*2/5, len(got.Values.Values()))
+		}
+	})
+
+	t.Run("should hold replicas of other zones with rendezvous", func(t *testing.T) {
+		// arrange
+		var (
+			sut      = RendezvousStrategy{}
+			snapshot = Snapshot{
+				ClientID: "my-client",
+				Size:     100,
+				Replicas: 1,
+				Ring: Ring{
+					{ClientID: "client-1", Name: "lease-a", Value: 0, Status: Leased, Zone: "zone-a"},
+					{ClientID: "client-2", Name: "lease-a", Value: 1, Status: Leased, Zone: "zone-a"},
+					{ClientID: "my-client", Name: "lease-a", Value: 2, Status: Leased, Zone: "zone-b"},
+				},
+			}
+		)
+
+		// act
+		got := sut.Analyze(snapshot)
+
+		// assert
+		assert.Equal(t, 100, len(got.Values.Values())+len(got.Replicas.Values()))
+		for _, value := range got.Replicas.Values() {
+			for _, owned := range got.Values.Values() {
+				if owned == value {
+					t.Errorf("value %d is both owned and a replica", value)
+				}
+			}
+		}
+	})
+}
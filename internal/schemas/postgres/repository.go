@@ -2,6 +2,9 @@ package postgres
 
 import (
 	"context"
+	sql2 "database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -46,13 +49,22 @@ type Repository struct {
 	sql map[string]string
 }
 
-func (s *Repository) InsertLease(ctx context.Context, clientID string, leaseName string, value int, ttl time.Duration, status lease.Status) error {
-	_, err := s.db.ExecContext(ctx, s.sql[insertLease],
-		leaseName,
-		clientID,
+func (s *Repository) InsertLease(ctx context.Context, info lease.Info, ttl time.Duration) error {
+	metadata, err := marshalMetadata(info.Metadata)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, s.sql[insertLease],
+		info.Name,
+		info.ClientID,
 		rfc8601.Format(ttl),
-		status,
-		value,
+		info.Status,
+		info.Value,
+		info.Weight,
+		info.MaxPartitions,
+		info.Zone,
+		metadata,
 	)
 
 	return err
@@ -62,27 +74,62 @@ func (s *Repository) GetAndRefreshLeases(ctx context.Context, names []string, cl
 	if err != nil {
 		return nil, err
 	}
+
+	return scanLeases(rows)
+}
+
+// GetLeases reads the leases without refreshing or evacuating any of them. Expired leases are left out.
+func (s *Repository) GetLeases(ctx context.Context, names []string) ([]lease.Info, error) {
+	rows, err := s.db.QueryContext(ctx, s.sql[selectLeases], names)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanLeases(rows)
+}
+
+type rowsScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Close() error
+	Err() error
+}
+
+func scanLeases(rows rowsScanner) ([]lease.Info, error) {
 	defer func() {
 		_ = rows.Close()
 	}()
 
 	var leases []lease.Info
 	for rows.Next() {
-		var info lease.Info
-		err = rows.Scan(
+		var (
+			info     lease.Info
+			metadata []byte
+			ageMS    int64
+		)
+		err := rows.Scan(
 			&info.Name,
 			&info.ClientID,
 			&info.TTL,
 			&info.Status,
 			&info.Value,
+			&info.Weight,
+			&info.MaxPartitions,
+			&info.Zone,
+			&metadata,
+			&ageMS,
 		)
 		if err != nil {
 			return nil, err
 		}
+		info.Metadata, err = unmarshalMetadata(metadata)
+		if err != nil {
+			return nil, err
+		}
+		info.Age = time.Duration(ageMS) * time.Millisecond
 		leases = append(leases, info)
 	}
-	return leases, nil
-
+	return leases, rows.Err()
 }
 
 func (s *Repository) SetLeaseStatus(ctx context.Context, clientID string, leaseName string, value int, status lease.Status) error {
@@ -94,6 +141,137 @@ func (s *Repository) DeleteLeases(ctx context.Context, clientID string) error {
 	return err
 }
 
+func (s *Repository) InsertLeaseDefinition(ctx context.Context, leaseName string, size int, strategy string, fixedSize bool) (lease.Definition, error) {
+	return scanDefinition(s.db.QueryRowContext(ctx, s.sql[insertLeaseDefinition], leaseName, size, strategy, fixedSize))
+}
+
+func (s *Repository) GetLeaseDefinitions(ctx context.Context, names []string) ([]lease.Definition, error) {
+	rows, err := s.db.QueryContext(ctx, s.sql[selectLeaseDefinitions], names)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var definitions []lease.Definition
+	for rows.Next() {
+		definition, err := scanDefinition(rows)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, definition)
+	}
+
+	return definitions, rows.Err()
+}
+
+func (s *Repository) UpdateLeaseSize(ctx context.Context, leaseName string, size int, quiet time.Duration) (lease.Definition, error) {
+	return scanDefinition(s.db.QueryRowContext(ctx, s.sql[updateLeaseSize], leaseName, size, rfc8601.Format(quiet), lease.Leased))
+}
+
+func (s *Repository) InsertResources(ctx context.Context, leaseName string, keys []string) error {
+	_, err := s.db.ExecContext(ctx, s.sql[insertResources], leaseName, keys)
+	return err
+}
+
+func (s *Repository) DeleteResources(ctx context.Context, leaseName string, keys []string) error {
+	_, err := s.db.ExecContext(ctx, s.sql[deleteResources], leaseName, keys)
+	return err
+}
+
+func (s *Repository) GetResources(ctx context.Context, names []string) ([]lease.Resource, error) {
+	rows, err := s.db.QueryContext(ctx, s.sql[selectResources], names)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var resources []lease.Resource
+	for rows.Next() {
+		var resource lease.Resource
+		err = rows.Scan(&resource.Name, &resource.Key)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+
+	return resources, rows.Err()
+}
+
+// AcquirePermit inserts the first free value below permits for the client, after expired permits are evacuated.
+// It reports false if all values are held by other clients.
+func (s *Repository) AcquirePermit(ctx context.Context, leaseName string, clientID string, ttl time.Duration, permits int) (lease.Permit, bool, error) {
+	_, err := s.db.ExecContext(ctx, s.sql[deleteExpiredPermits], leaseName)
+	if err != nil {
+		return lease.Permit{}, false, err
+	}
+
+	var permit lease.Permit
+	err = s.db.QueryRowContext(ctx, s.sql[insertPermit], leaseName, clientID, rfc8601.Format(ttl), lease.Leased, permits).Scan(&permit.Term, &permit.Value)
+	if errors.Is(err, sql2.ErrNoRows) {
+		return lease.Permit{}, false, nil
+	}
+	if err != nil {
+		return lease.Permit{}, false, err
+	}
+
+	return permit, true, nil
+}
+
+func (s *Repository) ReleasePermit(ctx context.Context, leaseName string, clientID string, value int) error {
+	_, err := s.db.ExecContext(ctx, s.sql[deletePermit], leaseName, clientID, value)
+	return err
+}
+
+func scanDefinition(row interface{ Scan(dest ...any) error }) (lease.Definition, error) {
+	var definition lease.Definition
+	err := row.Scan(
+		&definition.Name,
+		&definition.Size,
+		&definition.Strategy,
+		&definition.Created,
+		&definition.Generation,
+		&definition.Active,
+		&definition.FixedSize,
+	)
+	if err != nil {
+		return lease.Definition{}, err
+	}
+
+	return definition, nil
+}
+
 func (s *Repository) debugPrint(script string, args ...any) {
 	fmt.Printf("SQL:\n%sVALUES: %q\n", s.sql[script], args)
 }
+
+func marshalMetadata(metadata map[string]string) (string, error) {
+	if len(metadata) == 0 {
+		return "{}", nil
+	}
+
+	b, err := json.Marshal(metadata)
+	if err != nil {
+		return "", fmt.Errorf("[dbleases] invalid metadata: %w", err)
+	}
+
+	return string(b), nil
+}
+
+func unmarshalMetadata(b []byte) (map[string]string, error) {
+	var metadata map[string]string
+	err := json.Unmarshal(b, &metadata)
+	if err != nil {
+		return nil, fmt.Errorf("[dbleases] invalid metadata: %w", err)
+	}
+
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+
+	return metadata, nil
+}
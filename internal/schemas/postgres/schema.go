@@ -29,15 +29,38 @@ var migratorFiles = []string{
 const (
 	insertLease         = "insert_lease.tmpl"
 	selectRefreshLeases = "select_refresh_leases.tmpl"
+	selectLeases        = "select_leases.tmpl"
 	updateLeaseStatus   = "update_lease_status.tmpl"
 	deleteLeases        = "delete_leases.tmpl"
+
+	insertLeaseDefinition  = "insert_lease_definition.tmpl"
+	selectLeaseDefinitions = "select_lease_definitions.tmpl"
+	updateLeaseSize        = "update_lease_size.tmpl"
+
+	insertResources = "insert_resources.tmpl"
+	deleteResources = "delete_resources.tmpl"
+	selectResources = "select_resources.tmpl"
+
+	deleteExpiredPermits = "delete_expired_permits.tmpl"
+	insertPermit         = "insert_permit.tmpl"
+	deletePermit         = "delete_permit.tmpl"
 )
 
 var clientFiles = []string{
 	selectRefreshLeases,
+	selectLeases,
 	insertLease,
 	updateLeaseStatus,
 	deleteLeases,
+	insertLeaseDefinition,
+	selectLeaseDefinitions,
+	updateLeaseSize,
+	insertResources,
+	deleteResources,
+	selectResources,
+	deleteExpiredPermits,
+	insertPermit,
+	deletePermit,
 }
 
 type tableNames struct {
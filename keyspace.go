@@ -0,0 +1,90 @@
+package dbleases
+
+import (
+	"fmt"
+
+	"github.com/kyuff/dbleases/internal/hash"
+	"github.com/kyuff/dbleases/internal/lease"
+)
+
+const (
+	// keyspaceBits is the number of bits in the values of a KeyspaceLease.
+	keyspaceBits = 30
+	// keyspaceSize is the number of values in the Lease behind a KeyspaceLease.
+	keyspaceSize = 1 << keyspaceBits
+	// keyspaceShift maps a value of the Lease to the first hash of its range.
+	keyspaceShift = 64 - keyspaceBits
+)
+
+// KeyHash is the hash of a key in a KeyspaceLease.
+//
+// The hash is the 64 bit FNV-1a hash of the key bytes, so it can be reproduced in other languages.
+func KeyHash[K ~string | ~[]byte](key K) uint64 {
+	return hash.Key64(key)
+}
+
+// KeyRange is the hashes from and including From, up to but not including To.
+// The range that ends at the end of the keyspace has To = 0, as 2^64 wraps to 0.
+type KeyRange struct {
+	From uint64
+	To   uint64
+}
+
+// Contains reports if the hash is in the range.
+func (r KeyRange) Contains(h uint64) bool {
+	return h >= r.From && (r.To == 0 || h < r.To)
+}
+
+// KeyspaceLease is a Lease over the full uint64 hash keyspace. Each client owns
+// contiguous ranges of the keyspace, split and merged by the entries in the ring.
+//
+// The keyspace is divided in 2^30 slices of 2^34 hashes, which are the values of
+// the underlying Lease. A hash h is in the value h >> 34. The Lease is not exposed, as
+// listing its values would build slices of up to 2^30 values. Use Ranges and Owns.
+type KeyspaceLease struct {
+	lease *Lease
+}
+
+// KeyspaceLease returns the named KeyspaceLease. See Client.Lease.
+//
+// A KeyspaceLease must use the RingStrategy, as other strategies assign each value on its own.
+func (c *Client) KeyspaceLease(name string, options ...LeaseOption) (*KeyspaceLease, error) {
+	o := defaultLeaseOptions()
+	for _, opt := range options {
+		opt(&o)
+	}
+
+	if o.strategy == nil || o.strategy.Name() != RingStrategy().Name() {
+		return nil, fmt.Errorf("[dbleases] keyspace lease %q must use the ring strategy", name)
+	}
+
+	l, err := c.Lease(name, keyspaceSize, append(options, withFixedSize())...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyspaceLease{lease: l}, nil
+}
+
+// Ranges of the keyspace owned by the client in increasing order, as of the latest heartbeat.
+func (k *KeyspaceLease) Ranges() []KeyRange {
+	var ranges []KeyRange
+	for _, span := range lease.Spans(k.lease.Partitions()) {
+		ranges = append(ranges, KeyRange{
+			From: uint64(span.From) << keyspaceShift,
+			To:   uint64(span.To) << keyspaceShift,
+		})
+	}
+
+	return ranges
+}
+
+// Owns reports if the hash is in a range owned by the client, as of the latest heartbeat.
+func (k *KeyspaceLease) Owns(h uint64) bool {
+	return k.lease.Partitions().Contains(int(h >> keyspaceShift))
+}
+
+// OwnsKey reports if the KeyHash of the key is owned by the client.
+func (k *KeyspaceLease) OwnsKey(key string) bool {
+	return k.Owns(KeyHash(key))
+}
@@ -0,0 +1,60 @@
+package dbleases
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kyuff/dbleases/internal/assert"
+)
+
+func TestKeyspaceLease(t *testing.T) {
+	var (
+		newKeyspace = func(values PartitionSet) *KeyspaceLease {
+			return &KeyspaceLease{lease: &Lease{size: keyspaceSize, values: values}}
+		}
+	)
+
+	t.Run("should map values to ranges of the keyspace", func(t *testing.T) {
+		// arrange
+		var (
+			sut = newKeyspace(NewPartitionSet(0, 1, keyspaceSize-1))
+		)
+
+		// act
+		got := sut.Ranges()
+
+		// assert
+		assert.EqualSlice(t, []KeyRange{
+			{From: 0, To: 2 << 34},
+			{From: math.MaxUint64 - (1<<34 - 1), To: 0},
+		}, got)
+	})
+
+	t.Run("should own hashes in the ranges", func(t *testing.T) {
+		// arrange
+		var (
+			sut = newKeyspace(NewPartitionSet(1, keyspaceSize-1))
+		)
+
+		// assert
+		assert.Equal(t, false, sut.Owns(1<<34-1))
+		assert.Equal(t, true, sut.Owns(1<<34))
+		assert.Equal(t, true, sut.Owns(2<<34-1))
+		assert.Equal(t, false, sut.Owns(2<<34))
+		assert.Equal(t, true, sut.Owns(math.MaxUint64))
+		assert.Equal(t, true, KeyRange{From: math.MaxUint64 - 1, To: 0}.Contains(math.MaxUint64))
+	})
+
+	t.Run("should only use the ring strategy", func(t *testing.T) {
+		// arrange
+		var (
+			sut = newTestClient("my-client", nil)
+		)
+
+		// act
+		_, err := sut.KeyspaceLease("lease-a", WithStrategy(RendezvousStrategy()))
+
+		// assert
+		assert.Error(t, err)
+	})
+}
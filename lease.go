@@ -2,58 +2,234 @@ package dbleases
 
 import (
 	"context"
+	"math"
 	"sync"
+	"time"
 
 	"github.com/kyuff/dbleases/internal/hash"
+	"github.com/kyuff/dbleases/internal/lease"
 )
 
-func newLease(client *Client, name string, size int) *Lease {
+func newLease(client *Client, definition lease.Definition, opt LeaseOptions) *Lease {
 	return &Lease{
-		client:    client,
-		name:      name,
-		size:      size,
-		ringValue: []int{hash.Mod(client.ID, size)},
+		client:     client,
+		name:       definition.Name,
+		opt:        opt,
+		size:       definition.Size,
+		generation: definition.Generation,
+		active:     definition.Active,
+		ringValue:  opt.ringValues(client.ID, definition.Size),
+		changed:    make(chan struct{}),
 	}
 }
 
+// ringValues are the points in the ring a client registers for a Lease.
+// The first point is the hash of the client ID, and the virtual nodes after
+// it are spread with a score of the client ID and the node number.
+func ringValues(clientID string, size int, nodes int) []int {
+	var values = []int{hash.Mod(clientID, size)}
+	for i := 1; i < nodes; i++ {
+		values = append(values, int(hash.Score(clientID, i)%uint64(size)))
+	}
+
+	return values
+}
+
+// standbyValue is the point in the ring for a Standby client. It is negative,
+// so it never takes the place of a value in the Lease, even after a resize.
+func standbyValue(clientID string) int {
+	return -1 - hash.Hash(clientID)%(math.MaxInt32-1)
+}
+
 type Lease struct {
 	client *Client
 	name   string
-	size   int
+	opt    LeaseOptions
 
-	ringValue []int
+	mu         sync.RWMutex
+	size       int
+	generation int
+	active     bool
+	ringValue  []int
+	values     lease.Set
+	unassigned int
+	replicas   lease.Set
+	members    []Member
+	// changed is closed and replaced when the values change
+	changed chan struct{}
+	// refreshed is the local time the latest refresh of the entries was sent
+	refreshed time.Time
+	balanced  time.Time
+	previous  lease.Ring
+}
 
-	mu     sync.RWMutex
-	values []int
+// Size of the Lease. It changes when the Lease is resized by any client.
+func (m *Lease) Size() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.size
 }
 
+// Values owned by the client in increasing order, as of the latest heartbeat.
+// The slice is a copy, and can be changed by the caller.
 func (m *Lease) Values() []int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.values.Values()
+}
+
+// Partitions owned by the client, as of the latest heartbeat.
+func (m *Lease) Partitions() PartitionSet {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	return m.values
 }
 
-func (m *Lease) setValues(ctx context.Context, values []int) {
+// Unassigned is the number of values in the Lease that no client owns,
+// as of the latest heartbeat. Values are unassigned when clients are limited
+// by WithMaxPartitions, or while values are handed over between clients.
+func (m *Lease) Unassigned() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.unassigned
+}
+
+// Replicas are the values owned by clients in other zones, that the client
+// holds a replica of, as of the latest heartbeat. See WithReplicas.
+func (m *Lease) Replicas() []int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.replicas.Values()
+}
+
+// Members are all clients in the ring of the Lease with the values they own,
+// as of the latest heartbeat. Members are sorted by client ID. The slice is a copy.
+func (m *Lease) Members() []Member {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return lease.CopyMembers(m.members)
+}
+
+// changes is closed the next time the values of the Lease change.
+func (m *Lease) changes() <-chan struct{} {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.changed
+}
+
+func (m *Lease) setRefreshed(sent time.Time) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.refreshed = sent
+}
 
-	if !equal(m.values, values) {
-		m.client.opt.logger.InfofContext(ctx, "[dbleases] Lease %q for client %q set to %q", m.name, m.client.ID, presentIntegers(values))
+func (m *Lease) setReport(ctx context.Context, report lease.Report) {
+	m.setValues(ctx, report.Values)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.unassigned = report.Unassigned
+	m.replicas = report.Replicas
+	m.members = report.Members
+}
+
+func (m *Lease) setValues(ctx context.Context, values lease.Set) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.values.Equal(values) {
+		m.client.opt.logger.InfofContext(ctx, "[dbleases] Lease %q for client %q set to %q", m.name, m.client.ID, values)
+		close(m.changed)
+		m.changed = make(chan struct{})
 	}
 
 	m.values = values
 }
 
-func equal(a, b []int) bool {
-	if len(a) != len(b) {
+// setDefinition updates the Lease to the latest generation of its Definition.
+func (m *Lease) setDefinition(ctx context.Context, definition lease.Definition) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.generation != definition.Generation {
+		m.client.opt.logger.InfofContext(ctx, "[dbleases] Lease %q resized from %d to %d", m.name, m.size, definition.Size)
+		m.size = definition.Size
+		m.generation = definition.Generation
+		m.ringValue = m.opt.ringValues(m.client.ID, definition.Size)
+	}
+
+	m.active = definition.Active
+}
+
+// analyze the Ring of the Lease with the current Definition.
+// An inactive Lease reports no values while the previous generation is drained.
+func (m *Lease) analyze(ring lease.Ring) (lease.Report, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.active {
+		m.previous = nil
+		return lease.Report{}, false
+	}
+
+	previous := m.previous
+	m.previous = append(lease.Ring{}, ring...)
+
+	return m.opt.strategy.Analyze(lease.Snapshot{
+		ClientID:    m.client.ID,
+		Size:        m.size,
+		Ring:        ring,
+		Rebalancing: m.opt.rebalancing,
+		Quorum:      m.opt.quorum,
+		Standby:     m.opt.standby,
+		Previous:    previous,
+		Replicas:    m.opt.replicas,
+		Heartbeat:   m.client.opt.heartbeat,
+	}), true
+}
+
+// standbyRequest registers the Standby entry of the client again, if it has expired from the Ring.
+func (m *Lease) standbyRequest(ring lease.Ring) *lease.Request {
+	if !m.opt.standby {
+		return nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, info := range ring {
+		if info.ClientID == m.client.ID && info.Status == lease.Standby {
+			return nil
+		}
+	}
+
+	return &lease.Request{
+		ClientID:  m.client.ID,
+		LeaseName: m.name,
+		Value:     m.ringValue[0],
+		Status:    lease.Standby,
+	}
+}
+
+// allowBalance limits balancing by the client to once per Rebalancing Interval.
+// Requests to enter the Ring are always allowed.
+func (m *Lease) allowBalance(ring lease.Ring, request *lease.Request) bool {
+	if request == nil {
 		return false
 	}
 
-	for i := 0; i < len(a); i++ {
-		if a[i] != b[i] {
-			return false
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, info := range ring {
+		if info.ClientID == m.client.ID {
+			if time.Since(m.balanced) < m.opt.rebalancing.Interval {
+				return false
+			}
+			break
 		}
 	}
 
+	m.balanced = time.Now()
 	return true
 }
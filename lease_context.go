@@ -0,0 +1,56 @@
+package dbleases
+
+import (
+	"context"
+	"time"
+)
+
+// Context for work on a value of the Lease. It reports false, and a cancelled
+// context, if the value is not owned by the client.
+//
+// The context is cancelled with the cause ErrNotOwned the moment the value is no longer
+// owned by the client. Its deadline is the expiry of the entries of the client in the
+// database, as of the latest heartbeat, with the cause ErrLeaseExpired. The deadline is
+// not extended by later heartbeats, so get a new context for each unit of work.
+func (m *Lease) Context(parent context.Context, value int) (context.Context, bool) {
+	m.mu.RLock()
+	var (
+		owned   = m.values.Contains(value)
+		expiry  = m.refreshed.Add(m.client.opt.ttl)
+		changes = m.changed
+	)
+	m.mu.RUnlock()
+
+	ctx, cancel := context.WithCancelCause(parent)
+	if !owned {
+		cancel(ErrNotOwned)
+		return ctx, false
+	}
+
+	if !time.Now().Before(expiry) {
+		cancel(ErrLeaseExpired)
+		return ctx, false
+	}
+
+	ctx, cancelDeadline := context.WithDeadlineCause(ctx, expiry, ErrLeaseExpired)
+	go func() {
+		defer cancel(nil)
+		defer cancelDeadline()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-changes:
+				m.mu.RLock()
+				owned, changes = m.values.Contains(value), m.changed
+				m.mu.RUnlock()
+				if !owned {
+					cancel(ErrNotOwned)
+					return
+				}
+			}
+		}
+	}()
+
+	return ctx, true
+}
@@ -0,0 +1,102 @@
+package dbleases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyuff/dbleases/internal/assert"
+)
+
+func TestLeaseContext(t *testing.T) {
+	var (
+		newLease = func(values []int, refreshed time.Time) *Lease {
+			return &Lease{client: newTestClient("my-client", nil), name: "lease-a", changed: make(chan struct{}), values: NewPartitionSet(values...), refreshed: refreshed}
+		}
+	)
+
+	t.Run("should not give a context for a value that is not owned", func(t *testing.T) {
+		// arrange
+		var (
+			sut = newLease([]int{1, 2}, time.Now())
+		)
+
+		// act
+		ctx, ok := sut.Context(context.Background(), 3)
+
+		// assert
+		assert.Equal(t, false, ok)
+		assert.Equal(t, true, errors.Is(context.Cause(ctx), ErrNotOwned))
+	})
+
+	t.Run("should not give a context for an expired lease", func(t *testing.T) {
+		// arrange
+		var (
+			sut = newLease([]int{1, 2}, time.Now().Add(-time.Minute))
+		)
+
+		// act
+		ctx, ok := sut.Context(context.Background(), 1)
+
+		// assert
+		assert.Equal(t, false, ok)
+		assert.Equal(t, true, errors.Is(context.Cause(ctx), ErrLeaseExpired))
+	})
+
+	t.Run("should have the expiry of the lease as deadline", func(t *testing.T) {
+		// arrange
+		var (
+			refreshed = time.Now()
+			sut       = newLease([]int{1, 2}, refreshed)
+		)
+
+		// act
+		ctx, ok := sut.Context(context.Background(), 1)
+
+		// assert
+		assert.Equal(t, true, ok)
+		deadline, _ := ctx.Deadline()
+		assert.Equal(t, refreshed.Add(time.Second), deadline)
+		assert.NoError(t, ctx.Err())
+	})
+
+	t.Run("should cancel when the value is no longer owned", func(t *testing.T) {
+		// arrange
+		var (
+			sut = newLease([]int{1, 2}, time.Now())
+		)
+		ctx, ok := sut.Context(context.Background(), 1)
+		assert.Equal(t, true, ok)
+
+		// act
+		sut.setValues(context.Background(), NewPartitionSet(2, 3))
+
+		// assert
+		select {
+		case <-ctx.Done():
+			assert.Equal(t, true, errors.Is(context.Cause(ctx), ErrNotOwned))
+		case <-time.After(time.Second):
+			t.Fatal("context was not cancelled")
+		}
+	})
+
+	t.Run("should stay active while the value is owned", func(t *testing.T) {
+		// arrange
+		var (
+			sut = newLease([]int{1, 2}, time.Now())
+		)
+		ctx, ok := sut.Context(context.Background(), 1)
+		assert.Equal(t, true, ok)
+
+		// act
+		sut.setValues(context.Background(), NewPartitionSet(1, 3))
+
+		// assert
+		select {
+		case <-ctx.Done():
+			t.Fatal("context was cancelled")
+		case <-time.After(time.Millisecond * 50):
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package dbleases
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyuff/dbleases/internal/lease"
+)
+
+type LeaseOption func(o *LeaseOptions)
+
+type LeaseOptions struct {
+	maxPartitions int
+	strategy      Strategy
+	virtualNodes  int
+	rebalancing   lease.Rebalancing
+	quorum        lease.Quorum
+	standby       bool
+	replicas      int
+	// fixedSize is set by the types built on a Lease with a size they depend on
+	fixedSize bool
+}
+
+// ringValues are the points in the ring the client registers with the options.
+func (opt LeaseOptions) ringValues(clientID string, size int) []int {
+	if opt.standby {
+		return []int{standbyValue(clientID)}
+	}
+
+	return ringValues(clientID, size, opt.virtualNodes)
+}
+
+// status of the entries the client registers with the options.
+func (opt LeaseOptions) status() lease.Status {
+	if opt.standby {
+		return lease.Standby
+	}
+
+	return lease.Pending
+}
+
+func (opt LeaseOptions) validate() error {
+	if opt.maxPartitions < 0 {
+		return fmt.Errorf("[dbleases] max partitions must not be negative: %d", opt.maxPartitions)
+	}
+
+	if opt.virtualNodes < 1 {
+		return fmt.Errorf("[dbleases] virtual nodes must be at least 1: %d", opt.virtualNodes)
+	}
+
+	if opt.rebalancing.Threshold < 0 || opt.rebalancing.MaxMoves < 0 || opt.rebalancing.Interval < 0 || opt.rebalancing.Hold < 0 {
+		return fmt.Errorf("[dbleases] rebalancing must not be negative: %+v", opt.rebalancing)
+	}
+
+	if opt.quorum.MinMembers < 0 || opt.quorum.Window < 0 {
+		return fmt.Errorf("[dbleases] quorum must not be negative: %+v", opt.quorum)
+	}
+
+	if opt.replicas < 0 {
+		return fmt.Errorf("[dbleases] replicas must not be negative: %d", opt.replicas)
+	}
+
+	if opt.strategy == nil || opt.strategy.Name() == "" {
+		return fmt.Errorf("[dbleases] strategy must be named")
+	}
+
+	if opt.standby && opt.strategy.Name() != RingStrategy().Name() {
+		return fmt.Errorf("[dbleases] standby requires the ring strategy, got %q", opt.strategy.Name())
+	}
+
+	return nil
+}
+
+func defaultLeaseOptions() LeaseOptions {
+	var opts = []LeaseOption{
+		WithMaxPartitions(0),
+		WithStrategy(RingStrategy()),
+		WithVirtualNodes(1),
+		WithRebalancing(DefaultRebalancing()),
+	}
+	var o LeaseOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// WithMaxPartitions limits the number of values the client owns in a Lease, even if
+// it is the only client. Values above the limit are left unassigned. Zero means no limit.
+func WithMaxPartitions(n int) LeaseOption {
+	return func(o *LeaseOptions) {
+		o.maxPartitions = n
+	}
+}
+
+// WithStrategy sets the Strategy used to assign values in a Lease.
+// All clients of the Lease must use a Strategy with the same name.
+func WithStrategy(strategy Strategy) LeaseOption {
+	return func(o *LeaseOptions) {
+		o.strategy = strategy
+	}
+}
+
+// WithVirtualNodes registers k points in the ring for the client, instead of one.
+// More points give an even distribution of values from the start, instead of
+// relying on balancing when many clients start at once.
+func WithVirtualNodes(k int) LeaseOption {
+	return func(o *LeaseOptions) {
+		o.virtualNodes = k
+	}
+}
+
+// WithRebalancing configures how the client balances values with other clients in a Lease.
+func WithRebalancing(rebalancing Rebalancing) LeaseOption {
+	return func(o *LeaseOptions) {
+		o.rebalancing = rebalancing
+	}
+}
+
+// WithMinMembers holds back the values of a Lease until n clients are in the ring.
+// The values are then assigned to all clients at once, instead of the first client
+// taking all values and handing them over as others join.
+func WithMinMembers(n int) LeaseOption {
+	return func(o *LeaseOptions) {
+		o.quorum.MinMembers = n
+	}
+}
+
+// WithSettleWindow holds back the values of a Lease until the first client has been
+// in the ring for the window. Combined with WithMinMembers, the values are assigned
+// when either the clients are present or the window has passed.
+func WithSettleWindow(window time.Duration) LeaseOption {
+	return func(o *LeaseOptions) {
+		o.quorum.Window = window
+	}
+}
+
+// AsStandby registers the client as a hot standby in the Lease. A standby client
+// gets no values during normal operation, but takes over the values of a client
+// that leaves the ring. A standby client does not take part in balancing.
+//
+// Standby clients require the RingStrategy, as other strategies never hand values
+// to a standby client. Client.Lease returns an error for other strategies.
+func AsStandby() LeaseOption {
+	return func(o *LeaseOptions) {
+		o.standby = true
+	}
+}
+
+// WithReplicas makes the client hold replicas of values owned by other clients.
+// Each value has n replicas, held by clients in other zones than the owner and
+// each other. See Lease.Replicas and WithZone.
+func WithReplicas(n int) LeaseOption {
+	return func(o *LeaseOptions) {
+		o.replicas = n
+	}
+}
+
+// withFixedSize prevents Client.Resize of the Lease, as the size is given by the type built on it.
+func withFixedSize() LeaseOption {
+	return func(o *LeaseOptions) {
+		o.fixedSize = true
+	}
+}
@@ -0,0 +1,45 @@
+package dbleases
+
+import (
+	"testing"
+
+	"github.com/kyuff/dbleases/internal/assert"
+)
+
+func TestLeaseOptions(t *testing.T) {
+	var (
+		newOptions = func(options ...LeaseOption) LeaseOptions {
+			o := defaultLeaseOptions()
+			for _, opt := range options {
+				opt(&o)
+			}
+			return o
+		}
+	)
+
+	t.Run("should accept a standby client with the ring strategy", func(t *testing.T) {
+		// arrange
+		var (
+			sut = newOptions(WithStrategy(RingStrategy()), AsStandby())
+		)
+
+		// act
+		err := sut.validate()
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject a standby client with the rendezvous strategy", func(t *testing.T) {
+		// arrange
+		var (
+			sut = newOptions(WithStrategy(RendezvousStrategy()), AsStandby())
+		)
+
+		// act
+		err := sut.validate()
+
+		// assert
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,35 @@
+package dbleases
+
+import (
+	"testing"
+
+	"github.com/kyuff/dbleases/internal/assert"
+	"github.com/kyuff/dbleases/internal/hash"
+)
+
+func TestRingValues(t *testing.T) {
+	t.Run("should use the client hash as single node", func(t *testing.T) {
+		// act
+		got := ringValues("my-client", 100, 1)
+
+		// assert
+		assert.EqualSlice(t, []int{hash.Mod("my-client", 100)}, got)
+	})
+
+	t.Run("should spread virtual nodes in the ring", func(t *testing.T) {
+		// act
+		got := ringValues("my-client", 1000, 8)
+
+		// assert
+		assert.Equal(t, 8, len(got))
+		assert.Equal(t, hash.Mod("my-client", 1000), got[0])
+		var seen = make(map[int]bool)
+		for _, value := range got {
+			if value < 0 || value >= 1000 {
+				t.Errorf("value out of range: %d", value)
+			}
+			seen[value] = true
+		}
+		assert.Equal(t, 8, len(seen))
+	})
+}
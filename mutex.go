@@ -0,0 +1,54 @@
+package dbleases
+
+import "context"
+
+// mutexStrategy is the strategy name stored in the definition of a Mutex.
+const mutexStrategy = "mutex"
+
+// Mutex is a distributed lock over the leases table.
+//
+// The lock is an entry in the leases table, refreshed by the heartbeat of the client
+// and evacuated when it expires, like the entries of a Lease. The Context of the lock
+// is cancelled with ErrLockLost if the heartbeat fails to refresh it in time.
+type Mutex struct {
+	*permit
+}
+
+// Mutex returns the named Mutex. A name used by a Mutex cannot be used by a Lease or a Semaphore.
+func (c *Client) Mutex(name string) (*Mutex, error) {
+	p, err := c.permit(name, mutexStrategy, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Mutex{permit: p}, nil
+}
+
+// Lock blocks until the lock is acquired, or ctx is done.
+// The lock is tried again on each heartbeat interval.
+func (m *Mutex) Lock(ctx context.Context) error {
+	return m.acquire(ctx)
+}
+
+// TryLock acquires the lock if it is free, and reports if it was acquired.
+func (m *Mutex) TryLock(ctx context.Context) (bool, error) {
+	return m.tryAcquire(ctx)
+}
+
+// Unlock releases the lock. The lock is kept if it cannot be released in the database.
+func (m *Mutex) Unlock(ctx context.Context) error {
+	return m.release(ctx)
+}
+
+// Context of the lock. It is cancelled when the lock is released, or with the cause
+// ErrLockLost when it is lost. If the lock is not held, the context is already cancelled.
+func (m *Mutex) Context() context.Context {
+	return m.context()
+}
+
+// Token is a fencing token of the latest acquisition of the lock. It increases each
+// time the lock is acquired by any client, so a resource can reject writes with an
+// older token than it has seen.
+func (m *Mutex) Token() int64 {
+	return m.token()
+}
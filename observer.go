@@ -0,0 +1,209 @@
+package dbleases
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kyuff/dbleases/internal/lease"
+	"github.com/kyuff/dbleases/internal/split"
+)
+
+// observerEventBuffer is the number of Events an Observer buffers before dropping them.
+const observerEventBuffer = 64
+
+// Event is sent by an Observer when the members of a Lease, or the values they own, change.
+type Event struct {
+	Name    string
+	Members []Member
+}
+
+// Observer reads the ring of a set of leases on each heartbeat, without taking part in them.
+//
+// An Observer does not insert entries, refresh or evacuate expired entries, so it is never
+// assigned values and does not change the balance of a Lease. Expired entries are left out.
+type Observer struct {
+	opt   Options
+	repo  Repository
+	names []string
+
+	closed            atomic.Bool
+	heartbeatStopChan chan struct{}
+	events            chan Event
+
+	// subscribed is set by Events, so an Observer read only through Members sends no events
+	subscribed atomic.Bool
+
+	mu      sync.RWMutex
+	members map[string][]Member
+	// dropped is the number of events dropped since the latest event was sent
+	dropped int
+}
+
+// NewObserver starts observing the named leases.
+//
+// The members of a Lease are found with the Strategy it is defined with. The RingStrategy and
+// RendezvousStrategy are known by default, and other strategies are added with WithObservedStrategy.
+func NewObserver(db DB, names []string, options ...Option) (*Observer, error) {
+	o := defaultOptions()
+	for _, opt := range options {
+		opt(&o)
+	}
+
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.migrationTimeout)
+	defer cancel()
+	repo, err := o.repositoryFactory(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	observer := &Observer{
+		opt:               o,
+		repo:              repo,
+		names:             append([]string{}, names...),
+		heartbeatStopChan: make(chan struct{}),
+		events:            make(chan Event, observerEventBuffer),
+		members:           make(map[string][]Member),
+	}
+	observer.startHeartbeat()
+
+	return observer, nil
+}
+
+// Members of the named Lease as of the latest heartbeat. See Lease.Members.
+func (o *Observer) Members(name string) []Member {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return lease.CopyMembers(o.members[name])
+}
+
+// Events are sent when the members of an observed Lease change, from the first call to Events.
+// Events are dropped if the channel is full. The channel is closed by Close.
+func (o *Observer) Events() <-chan Event {
+	o.subscribed.Store(true)
+	return o.events
+}
+
+func (o *Observer) Close() error {
+	if o.closed.Swap(true) {
+		return nil
+	}
+	o.heartbeatStopChan <- struct{}{}
+	<-o.heartbeatStopChan
+	return nil
+}
+
+func (o *Observer) startHeartbeat() {
+	pump := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), o.opt.heartbeatTimeout)
+		defer cancel()
+		err := o.heartbeat(ctx)
+		if err != nil {
+			o.opt.logger.ErrorfContext(ctx, "[dbleases] Observer heartbeat failed: %s", err)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(o.opt.heartbeat)
+		defer ticker.Stop()
+
+		defer func() {
+			close(o.events)
+			o.heartbeatStopChan <- struct{}{}
+		}()
+
+		pump()
+
+		for {
+			select {
+			case <-o.heartbeatStopChan:
+				return
+			case <-ticker.C:
+				pump()
+			}
+		}
+	}()
+}
+
+func (o *Observer) heartbeat(ctx context.Context) error {
+	allLeases, err := o.repo.GetLeases(ctx, o.names)
+	if err != nil {
+		return err
+	}
+
+	definitions, err := o.repo.GetLeaseDefinitions(ctx, o.names)
+	if err != nil {
+		return err
+	}
+
+	leasesByName := split.By(lease.Ring(allLeases), func(lease lease.Info) string {
+		return lease.Name
+	})
+
+	for _, definition := range definitions {
+		if !definition.Active {
+			continue
+		}
+
+		strategy, ok := o.opt.strategies[definition.Strategy]
+		if !ok {
+			o.opt.logger.ErrorfContext(ctx, "[dbleases] Unknown strategy %q for lease %q", definition.Strategy, definition.Name)
+			continue
+		}
+
+		report := strategy.Analyze(lease.Snapshot{
+			Size:        definition.Size,
+			Ring:        leasesByName[definition.Name],
+			Rebalancing: lease.DefaultRebalancing,
+		})
+
+		o.setMembers(ctx, definition.Name, report.Members)
+	}
+
+	return nil
+}
+
+func (o *Observer) setMembers(ctx context.Context, name string, members []Member) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	previous, known := o.members[name]
+	o.members[name] = members
+	if known && sameMembers(previous, members) {
+		return
+	}
+
+	if !o.subscribed.Load() {
+		return
+	}
+
+	select {
+	case o.events <- Event{Name: name, Members: lease.CopyMembers(members)}:
+		if o.dropped > 0 {
+			o.opt.logger.InfofContext(ctx, "[dbleases] Observer dropped %d events, as the Events channel was full", o.dropped)
+			o.dropped = 0
+		}
+	default:
+		o.dropped++
+	}
+}
+
+// sameMembers reports if a and b have the same clients owning the same values with the same status.
+func sameMembers(a, b []Member) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].ClientID != b[i].ClientID || a[i].Status != b[i].Status || !a[i].Values.Equal(b[i].Values) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,57 @@
+package dbleases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyuff/dbleases/internal/assert"
+	"github.com/kyuff/dbleases/internal/logger"
+)
+
+func TestObserverEvents(t *testing.T) {
+	var (
+		newObserver = func() *Observer {
+			return &Observer{
+				opt:     Options{logger: logger.NewNoop()},
+				events:  make(chan Event, 1),
+				members: make(map[string][]Member),
+			}
+		}
+		members = func(clientID string) []Member {
+			return []Member{{ClientID: clientID, Values: NewPartitionSet(1)}}
+		}
+	)
+
+	t.Run("should not send events without a subscriber", func(t *testing.T) {
+		// arrange
+		var (
+			sut = newObserver()
+		)
+
+		// act
+		sut.setMembers(context.Background(), "lease-a", members("client-a"))
+
+		// assert
+		assert.Equal(t, 0, len(sut.events))
+		assert.Equal(t, "client-a", sut.Members("lease-a")[0].ClientID)
+	})
+
+	t.Run("should count events dropped on a full channel", func(t *testing.T) {
+		// arrange
+		var (
+			sut    = newObserver()
+			events = sut.Events()
+		)
+
+		// act
+		sut.setMembers(context.Background(), "lease-a", members("client-a"))
+		sut.setMembers(context.Background(), "lease-a", members("client-b"))
+		sut.setMembers(context.Background(), "lease-a", members("client-c"))
+
+		// assert
+		assert.Equal(t, 2, sut.dropped)
+		assert.Equal(t, "client-a", (<-events).Members[0].ClientID)
+		sut.setMembers(context.Background(), "lease-a", members("client-d"))
+		assert.Equal(t, 0, sut.dropped)
+	})
+}
@@ -19,6 +19,10 @@ type Options struct {
 	heartbeatTimeout  time.Duration
 	repositoryFactory func(ctx context.Context, db DB) (Repository, error)
 	logger            Logger
+	weight            int
+	zone              string
+	metadata          map[string]string
+	strategies        map[string]Strategy
 }
 
 func (opt Options) validate() error {
@@ -30,6 +34,10 @@ func (opt Options) validate() error {
 		return fmt.Errorf("[dbleases] ttl must be higher than 1 second: %s", opt.ttl)
 	}
 
+	if opt.weight < 1 {
+		return fmt.Errorf("[dbleases] weight must be at least 1: %d", opt.weight)
+	}
+
 	return nil
 }
 
@@ -41,6 +49,9 @@ func defaultOptions() Options {
 		WithMigrationTimeout(time.Second * 5),
 		withHeartbeatTimeout(time.Second),
 		WithSlog(slog.Default()),
+		WithWeight(1),
+		WithObservedStrategy(RingStrategy()),
+		WithObservedStrategy(RendezvousStrategy()),
 	}
 	var o Options
 	for _, opt := range opts {
@@ -82,6 +93,43 @@ func WithTTL(ttl time.Duration) Option {
 	}
 }
 
+// WithWeight sets the share of values the client takes in leases relative to other clients.
+// A client with weight 4 is balanced to twice the values of a client with weight 2.
+func WithWeight(weight int) Option {
+	return func(o *Options) {
+		o.weight = weight
+	}
+}
+
+// WithZone places the client in a zone, such as an availability zone.
+// Each zone is balanced to an equal share of the values, and replicas are held in other zones.
+func WithZone(zone string) Option {
+	return func(o *Options) {
+		o.zone = zone
+	}
+}
+
+// WithMetadata publishes metadata with the entries of the client in all leases,
+// such as hostname, version and address. Other clients see it in the Ring.
+func WithMetadata(metadata map[string]string) Option {
+	return func(o *Options) {
+		o.metadata = make(map[string]string, len(metadata))
+		for key, value := range metadata {
+			o.metadata[key] = value
+		}
+	}
+}
+
+// WithObservedStrategy makes an Observer able to find the members of leases defined with the Strategy.
+func WithObservedStrategy(strategy Strategy) Option {
+	return func(o *Options) {
+		if o.strategies == nil {
+			o.strategies = make(map[string]Strategy)
+		}
+		o.strategies[strategy.Name()] = strategy
+	}
+}
+
 func WithSlog(l *slog.Logger) Option {
 	return func(o *Options) {
 		o.logger = logger2.NewSlog(l)
@@ -0,0 +1,18 @@
+package dbleases
+
+import "github.com/kyuff/dbleases/internal/lease"
+
+// PartitionSet is an immutable set of partitions, stored as spans of consecutive partitions.
+//
+// The zero value is an empty set. Its String format is "n/ranges", such as "5/0-3,7".
+type PartitionSet = lease.Set
+
+// NewPartitionSet of the partitions. The partitions need not be sorted, and duplicates are ignored.
+func NewPartitionSet(partitions ...int) PartitionSet {
+	return lease.NewSet(partitions...)
+}
+
+// ParsePartitionSet from the format of PartitionSet.String.
+func ParsePartitionSet(s string) (PartitionSet, error) {
+	return lease.ParseSet(s)
+}
@@ -0,0 +1,115 @@
+package dbleases
+
+import (
+	"testing"
+
+	"github.com/kyuff/dbleases/internal/assert"
+)
+
+func TestPartitionSet(t *testing.T) {
+	t.Run("should build ranges from unsorted partitions", func(t *testing.T) {
+		// act
+		got := NewPartitionSet(7, 2, 0, 1, 3, 3)
+
+		// assert
+		assert.Equal(t, "5/0-3,7", got.String())
+		assert.Equal(t, 5, got.Len())
+		assert.EqualSlice(t, []int{0, 1, 2, 3, 7}, got.Values())
+	})
+
+	t.Run("should not change the partitions", func(t *testing.T) {
+		// arrange
+		var (
+			partitions = []int{3, 1, 2}
+		)
+
+		// act
+		_ = NewPartitionSet(partitions...)
+
+		// assert
+		assert.EqualSlice(t, []int{3, 1, 2}, partitions)
+	})
+
+	t.Run("should contain partitions in the ranges", func(t *testing.T) {
+		// arrange
+		var (
+			sut = NewPartitionSet(0, 1, 2, 3, 7)
+		)
+
+		// assert
+		assert.Equal(t, true, sut.Contains(0))
+		assert.Equal(t, true, sut.Contains(3))
+		assert.Equal(t, true, sut.Contains(7))
+		assert.Equal(t, false, sut.Contains(4))
+		assert.Equal(t, false, sut.Contains(8))
+		assert.Equal(t, false, PartitionSet{}.Contains(0))
+	})
+
+	t.Run("should union sets", func(t *testing.T) {
+		// arrange
+		var (
+			a = NewPartitionSet(0, 1, 2, 8)
+			b = NewPartitionSet(3, 4, 9, 12)
+		)
+
+		// act
+		got := a.Union(b)
+
+		// assert
+		assert.Equal(t, "8/0-4,8-9,12", got.String())
+	})
+
+	t.Run("should diff sets", func(t *testing.T) {
+		// arrange
+		var (
+			a = NewPartitionSet(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+			b = NewPartitionSet(0, 3, 4, 9, 12)
+		)
+
+		// act
+		got := a.Diff(b)
+
+		// assert
+		assert.Equal(t, "6/1-2,5-8", got.String())
+		assert.Equal(t, "0/-", b.Diff(b).String())
+		assert.Equal(t, "1/12", b.Diff(a).String())
+	})
+
+	t.Run("should stop iterating when yield returns false", func(t *testing.T) {
+		// arrange
+		var (
+			sut = NewPartitionSet(0, 1, 2, 5)
+			got []int
+		)
+
+		// act
+		sut.Each(func(partition int) bool {
+			got = append(got, partition)
+			return partition < 2
+		})
+
+		// assert
+		assert.EqualSlice(t, []int{0, 1, 2}, got)
+	})
+
+	t.Run("should parse the string format", func(t *testing.T) {
+		for _, s := range []string{"0/-", "1/5", "5/0-3,7", "6/5-6,8,10-12"} {
+			// act
+			got, err := ParsePartitionSet(s)
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, s, got.String())
+		}
+	})
+
+	t.Run("should fail to parse an invalid format", func(t *testing.T) {
+		for _, s := range []string{"", "5", "2/0-3", "x/1", "1/a", "2/3-1"} {
+			// act
+			_, err := ParsePartitionSet(s)
+
+			// assert
+			assert.Error(t, err)
+		}
+	})
+}
@@ -0,0 +1,241 @@
+package dbleases
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kyuff/dbleases/internal/lease"
+)
+
+// permit is a value of a lease held by the client, such as the lock of a Mutex or a
+// permit of a Semaphore. It is refreshed by the heartbeat of the client and evacuated
+// when it expires, like the entries of a Lease.
+type permit struct {
+	client  *Client
+	kind    string
+	name    string
+	permits int
+
+	mu     sync.Mutex
+	held   bool
+	value  int
+	term   int64
+	expiry time.Time
+	// acquired is when the entry of the permit was committed, and is in every later read of the ring
+	acquired time.Time
+	ctx      context.Context
+	cancel   context.CancelCauseFunc
+}
+
+// permit returns the named permit of the kind, and defines it with the number of permits.
+// A name can only be used by one kind, as the kind is stored as the strategy of the definition.
+func (c *Client) permit(name string, kind string, permits int) (*permit, error) {
+	c.leaseMux.Lock()
+	defer c.leaseMux.Unlock()
+
+	if p, ok := c.permits[name]; ok {
+		if p.kind != kind {
+			return nil, fmt.Errorf("[dbleases] %s %q is used as a %s: %w", kind, name, p.kind, ErrStrategyMismatch)
+		}
+		if p.permits != permits {
+			return nil, fmt.Errorf("[dbleases] %s %q has %d permits, got %d: %w", kind, name, p.permits, permits, ErrSizeMismatch)
+		}
+		return p, nil
+	}
+
+	if permits <= 0 {
+		return nil, fmt.Errorf("[dbleases] %s %q must have a positive number of permits: %d", kind, name, permits)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.opt.heartbeatTimeout)
+	defer cancel()
+
+	definition, err := c.repo.InsertLeaseDefinition(ctx, name, permits, kind, true)
+	if err != nil {
+		return nil, fmt.Errorf("[dbleases] failed to define %s %q: %w", kind, name, err)
+	}
+
+	if definition.Strategy != kind {
+		return nil, fmt.Errorf("[dbleases] %s %q is defined with strategy %q: %w", kind, name, definition.Strategy, ErrStrategyMismatch)
+	}
+
+	if definition.Size != permits {
+		return nil, fmt.Errorf("[dbleases] %s %q is defined with %d permits, got %d: %w", kind, name, definition.Size, permits, ErrSizeMismatch)
+	}
+
+	p := &permit{client: c, kind: kind, name: name, permits: permits}
+	c.permits[name] = p
+	c.permitNames = append(c.permitNames, name)
+
+	c.heartbeatStart.Do(c.startHeartbeat)
+	return p, nil
+}
+
+// acquire blocks until a permit is acquired, or ctx is done.
+// A permit is tried again on each heartbeat interval.
+func (p *permit) acquire(ctx context.Context) error {
+	for {
+		ok, err := p.tryAcquire(ctx)
+		if err != nil || ok {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(p.client.opt.heartbeat):
+		}
+	}
+}
+
+// tryAcquire a permit if one is free, and report if it was acquired.
+func (p *permit) tryAcquire(ctx context.Context) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.held {
+		return false, fmt.Errorf("[dbleases] %s %q is already held by client %s", p.kind, p.name, p.client.ID)
+	}
+
+	sent := time.Now()
+	acquired, ok, err := p.client.repo.AcquirePermit(ctx, p.name, p.client.ID, p.client.opt.ttl, p.permits)
+	if err != nil {
+		return false, fmt.Errorf("[dbleases] failed to acquire %s %q: %w", p.kind, p.name, err)
+	}
+	if !ok {
+		return false, nil
+	}
+
+	p.held = true
+	p.value = acquired.Value
+	p.term = acquired.Term
+	p.expiry = sent.Add(p.client.opt.ttl)
+	p.acquired = time.Now()
+	p.ctx, p.cancel = context.WithCancelCause(context.Background())
+	go p.watch(p.ctx)
+
+	return true, nil
+}
+
+// release the permit. The permit is kept if it cannot be released in the database.
+func (p *permit) release(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.held {
+		return fmt.Errorf("[dbleases] %s %q is not held by client %s", p.kind, p.name, p.client.ID)
+	}
+
+	err := p.client.repo.ReleasePermit(ctx, p.name, p.client.ID, p.value)
+	if err != nil {
+		return fmt.Errorf("[dbleases] failed to release %s %q: %w", p.kind, p.name, err)
+	}
+
+	p.drop(context.Canceled)
+	return nil
+}
+
+// context of the permit. If the permit is not held, the context is already cancelled.
+func (p *permit) context() context.Context {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.held {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(ErrLockLost)
+		return ctx
+	}
+
+	return p.ctx
+}
+
+func (p *permit) token() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.term
+}
+
+// watch the expiry of the permit, and lose it if it is not refreshed in time.
+func (p *permit) watch(ctx context.Context) {
+	for {
+		p.mu.Lock()
+		wait := time.Until(p.expiry)
+		if wait <= 0 {
+			if p.held && p.ctx == ctx {
+				p.client.opt.logger.ErrorfContext(ctx, "[dbleases] %s %q lost by client %s: not refreshed", p.kind, p.name, p.client.ID)
+				p.drop(ErrLockLost)
+			}
+			p.mu.Unlock()
+			return
+		}
+		p.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// refresh the permit from the entries read by a heartbeat sent at sent.
+// It returns the values of entries of the client that the permit no longer holds, which must be released.
+//
+// A heartbeat sent before the permit was acquired may have read the ring without its
+// entry, so it is ignored and the next heartbeat refreshes the permit.
+func (p *permit) refresh(sent time.Time, entries lease.Ring) []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if sent.Before(p.acquired) {
+		return nil
+	}
+
+	var (
+		found bool
+		stale []int
+	)
+	for _, entry := range entries {
+		if entry.ClientID != p.client.ID {
+			continue
+		}
+
+		if p.held && entry.Value == p.value {
+			found = true
+			continue
+		}
+
+		stale = append(stale, entry.Value)
+	}
+
+	if !p.held {
+		return stale
+	}
+
+	if !found {
+		p.client.opt.logger.ErrorfContext(p.ctx, "[dbleases] %s %q lost by client %s: evacuated", p.kind, p.name, p.client.ID)
+		p.drop(ErrLockLost)
+		return stale
+	}
+
+	p.expiry = sent.Add(p.client.opt.ttl)
+	return stale
+}
+
+// close the permit when the client is closed, as the entries of the client are deleted.
+func (p *permit) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.held {
+		p.drop(context.Canceled)
+	}
+}
+
+// drop the permit locally. The caller must hold mu.
+func (p *permit) drop(cause error) {
+	p.held = false
+	p.cancel(cause)
+}
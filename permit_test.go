@@ -0,0 +1,120 @@
+package dbleases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyuff/dbleases/internal/assert"
+	"github.com/kyuff/dbleases/internal/lease"
+)
+
+func TestPermit(t *testing.T) {
+	var (
+		newPermit = func(value int, expiry time.Time) *permit {
+			p := &permit{client: newTestClient("my-client", nil), kind: semaphoreStrategy, name: "permit-a", permits: 3, held: true, value: value, term: 7, expiry: expiry}
+			p.ctx, p.cancel = context.WithCancelCause(context.Background())
+			return p
+		}
+		entry = func(clientID string, value int) lease.Info {
+			return lease.Info{Name: "permit-a", ClientID: clientID, Value: value}
+		}
+	)
+
+	t.Run("should give a cancelled context when not held", func(t *testing.T) {
+		// arrange
+		var (
+			sut = &permit{client: newTestClient("my-client", nil), kind: mutexStrategy, name: "permit-a", permits: 1}
+		)
+
+		// act
+		ctx := sut.context()
+
+		// assert
+		assert.Equal(t, true, errors.Is(context.Cause(ctx), ErrLockLost))
+	})
+
+	t.Run("should keep the lock when refreshed", func(t *testing.T) {
+		// arrange
+		var (
+			sent = time.Now()
+			sut  = newPermit(1, sent.Add(-time.Millisecond))
+		)
+
+		// act
+		stale := sut.refresh(sent, lease.Ring{entry("other-client", 0), entry("my-client", 1)})
+
+		// assert
+		assert.EqualSlice(t, []int(nil), stale)
+		assert.Equal(t, sent.Add(time.Second), sut.expiry)
+		assert.NoError(t, sut.context().Err())
+		assert.Equal(t, int64(7), sut.token())
+	})
+
+	t.Run("should ignore a heartbeat sent before the permit was acquired", func(t *testing.T) {
+		// arrange
+		var (
+			expiry = time.Now().Add(time.Second)
+			sut    = newPermit(1, expiry)
+			ctx    = sut.context()
+		)
+		sut.acquired = time.Now()
+
+		// act
+		stale := sut.refresh(sut.acquired.Add(-time.Millisecond), lease.Ring{entry("other-client", 0)})
+
+		// assert
+		assert.EqualSlice(t, []int(nil), stale)
+		assert.Equal(t, expiry, sut.expiry)
+		assert.NoError(t, ctx.Err())
+	})
+
+	t.Run("should lose the lock when evacuated", func(t *testing.T) {
+		// arrange
+		var (
+			sut = newPermit(1, time.Now().Add(time.Second))
+			ctx = sut.context()
+		)
+
+		// act
+		stale := sut.refresh(time.Now(), lease.Ring{entry("other-client", 1)})
+
+		// assert
+		assert.EqualSlice(t, []int(nil), stale)
+		assert.Equal(t, true, errors.Is(context.Cause(ctx), ErrLockLost))
+	})
+
+	t.Run("should release entries of permits that are not held", func(t *testing.T) {
+		// arrange
+		var (
+			sut = newPermit(1, time.Now().Add(time.Second))
+		)
+		sut.close()
+
+		// act
+		stale := sut.refresh(time.Now(), lease.Ring{entry("my-client", 1), entry("my-client", 2)})
+
+		// assert
+		assert.EqualSlice(t, []int{1, 2}, stale)
+	})
+
+	t.Run("should lose the lock when not refreshed in time", func(t *testing.T) {
+		// arrange
+		var (
+			sut = newPermit(1, time.Now().Add(time.Millisecond*10))
+			ctx = sut.context()
+		)
+
+		// act
+		go sut.watch(ctx)
+
+		// assert
+		select {
+		case <-ctx.Done():
+			assert.Equal(t, true, errors.Is(context.Cause(ctx), ErrLockLost))
+		case <-time.After(time.Second):
+			t.Fatal("context was not cancelled")
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package dbleases
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// resourceMembershipSize is the size of the Lease with the partitions of the keys of a ResourceLease.
+const resourceMembershipSize = 1024
+
+// ResourceLease is a lease over a dynamic set of named resources, such as tenant IDs
+// or queue names. Resources are added and removed at runtime by any client.
+//
+// A key is owned by the client that owns its partition, PartitionOf(key, 1024), in a
+// Lease with the same name. Keys move between clients with their partitions, so a key
+// is never owned by two clients, and adding or removing a key does not move others.
+//
+// The partitions are balanced between the clients, not the keys, so the keys are only
+// balanced statistically. With few keys, a client may own many more keys than another,
+// or none at all.
+type ResourceLease struct {
+	client *Client
+	lease  *Lease
+
+	mu sync.RWMutex
+	// keys of all resources in the lease, as of the latest heartbeat
+	keys []string
+}
+
+// ResourceLease returns the named ResourceLease. The options are applied to the Lease of its partitions.
+func (c *Client) ResourceLease(name string, options ...LeaseOption) (*ResourceLease, error) {
+	l, err := c.Lease(name, resourceMembershipSize, append(options, withFixedSize())...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.leaseMux.Lock()
+	defer c.leaseMux.Unlock()
+
+	if r, ok := c.resources[name]; ok {
+		return r, nil
+	}
+
+	r := &ResourceLease{client: c, lease: l}
+	c.resources[name] = r
+	c.resourceNames = append(c.resourceNames, name)
+
+	return r, nil
+}
+
+// Keys owned by the client in sorted order. The resources are as of the latest heartbeat.
+func (r *ResourceLease) Keys() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return ownedKeys(r.keys, r.lease.Partitions())
+}
+
+// Add resources to the lease. Keys that are already added are ignored.
+// The keys are seen by the owners of their partitions at their next heartbeat.
+func (r *ResourceLease) Add(ctx context.Context, keys ...string) error {
+	err := r.client.repo.InsertResources(ctx, r.lease.name, keys)
+	if err != nil {
+		return fmt.Errorf("[dbleases] failed to add resources to lease %q: %w", r.lease.name, err)
+	}
+
+	return nil
+}
+
+// Remove resources from the lease. The keys are released by their owner at its next heartbeat.
+func (r *ResourceLease) Remove(ctx context.Context, keys ...string) error {
+	err := r.client.repo.DeleteResources(ctx, r.lease.name, keys)
+	if err != nil {
+		return fmt.Errorf("[dbleases] failed to remove resources from lease %q: %w", r.lease.name, err)
+	}
+
+	return nil
+}
+
+// setResources of the lease from the latest heartbeat.
+func (r *ResourceLease) setResources(ctx context.Context, keys []string) {
+	keys = append([]string(nil), keys...)
+	sort.Strings(keys)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !equalStrings(r.keys, keys) {
+		owned := ownedKeys(keys, r.lease.Partitions())
+		r.client.opt.logger.InfofContext(ctx, "[dbleases] Resource lease %q for client %q set to %d keys of %d", r.lease.name, r.client.ID, len(owned), len(keys))
+	}
+
+	r.keys = keys
+}
+
+// ownedKeys are the keys with a partition in partitions.
+func ownedKeys(keys []string, partitions PartitionSet) []string {
+	var owned []string
+	for _, key := range keys {
+		if partitions.Contains(PartitionOf(key, resourceMembershipSize)) {
+			owned = append(owned, key)
+		}
+	}
+
+	return owned
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,61 @@
+package dbleases
+
+import (
+	"testing"
+
+	"github.com/kyuff/dbleases/internal/assert"
+)
+
+func TestOwnedKeys(t *testing.T) {
+	var (
+		keys = []string{"tenant-a", "tenant-b", "tenant-c", "tenant-d"}
+	)
+
+	t.Run("should own the keys in owned partitions", func(t *testing.T) {
+		// arrange
+		var (
+			partitions = NewPartitionSet(PartitionOf("tenant-b", resourceMembershipSize), PartitionOf("tenant-d", resourceMembershipSize))
+		)
+
+		// act
+		got := ownedKeys(keys, partitions)
+
+		// assert
+		assert.EqualSlice(t, []string{"tenant-b", "tenant-d"}, got)
+	})
+
+	t.Run("should split the keys between the owners of the partitions", func(t *testing.T) {
+		// arrange
+		lower, err := ParsePartitionSet("512/0-511")
+		assert.NoError(t, err)
+		upper, err := ParsePartitionSet("512/512-1023")
+		assert.NoError(t, err)
+
+		// act
+		gotLower := ownedKeys(keys, lower)
+		gotUpper := ownedKeys(keys, upper)
+
+		// assert
+		assert.Equal(t, len(keys), len(gotLower)+len(gotUpper))
+		for _, key := range gotLower {
+			assert.Equal(t, false, upper.Contains(PartitionOf(key, resourceMembershipSize)))
+		}
+	})
+
+	t.Run("should balance a small key set only by partitions", func(t *testing.T) {
+		// arrange
+		lower, err := ParsePartitionSet("512/0-511")
+		assert.NoError(t, err)
+		upper, err := ParsePartitionSet("512/512-1023")
+		assert.NoError(t, err)
+
+		// act
+		gotLower := ownedKeys(keys, lower)
+		gotUpper := ownedKeys(keys, upper)
+
+		// assert
+		// all keys hash to partitions in the upper half, so the owner of the lower half has none
+		assert.EqualSlice(t, []string(nil), gotLower)
+		assert.EqualSlice(t, keys, gotUpper)
+	})
+}
@@ -0,0 +1,46 @@
+package dbleases
+
+import "github.com/kyuff/dbleases/internal/hash"
+
+// PartitionOf is the partition of a key in a Lease of the given size.
+//
+// The partition is the 32 bit FNV-1 hash of the key bytes modulo the size:
+//
+//	partition = fnv1_32(key) % size
+//
+// The hash is stable and can be reproduced in other languages to route keys
+// to the same partitions.
+func PartitionOf[K ~string | ~[]byte](key K, size int) int {
+	if size <= 0 {
+		return 0
+	}
+
+	return int(hash.Key(key) % uint32(size))
+}
+
+// PartitionOf is the partition of a key in the Lease with its current size. See PartitionOf.
+func (m *Lease) PartitionOf(key string) int {
+	return PartitionOf(key, m.Size())
+}
+
+// PartitionOfBytes is PartitionOf for a key in bytes, without converting it to a string.
+func (m *Lease) PartitionOfBytes(key []byte) int {
+	return PartitionOf(key, m.Size())
+}
+
+// Owns reports if the partition of a key is owned by the client, as of the latest heartbeat.
+func (m *Lease) Owns(key string) bool {
+	return owns(m, key)
+}
+
+// OwnsBytes is Owns for a key in bytes, without converting it to a string.
+func (m *Lease) OwnsBytes(key []byte) bool {
+	return owns(m, key)
+}
+
+func owns[K ~string | ~[]byte](m *Lease, key K) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.values.Contains(PartitionOf(key, m.size))
+}
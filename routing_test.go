@@ -0,0 +1,56 @@
+package dbleases
+
+import (
+	"testing"
+
+	"github.com/kyuff/dbleases/internal/assert"
+)
+
+func TestPartitionOf(t *testing.T) {
+	t.Run("should use fnv1 32 modulo the size", func(t *testing.T) {
+		// act
+		got := PartitionOf("stream type", 100)
+
+		// assert
+		assert.Equal(t, 1423569895%100, got)
+	})
+
+	t.Run("should give the same partition for strings and bytes", func(t *testing.T) {
+		// act
+		got := PartitionOf([]byte("stream type"), 100)
+
+		// assert
+		assert.Equal(t, PartitionOf("stream type", 100), got)
+	})
+
+	t.Run("should report ownership of a key", func(t *testing.T) {
+		// arrange
+		var (
+			sut = &Lease{size: 100, values: NewPartitionSet(10, 95, 96)}
+		)
+
+		// act
+		got := sut.Owns("stream type")
+
+		// assert
+		assert.Equal(t, 95, sut.PartitionOf("stream type"))
+		assert.Equal(t, true, got)
+		assert.Equal(t, false, sut.Owns("x----------------y---------------z"))
+	})
+
+	t.Run("should report ownership of a key in bytes", func(t *testing.T) {
+		// arrange
+		var (
+			sut = &Lease{size: 100, values: NewPartitionSet(10, 95, 96)}
+			key = []byte("stream type")
+		)
+
+		// act
+		got := sut.OwnsBytes(key)
+
+		// assert
+		assert.Equal(t, 95, sut.PartitionOfBytes(key))
+		assert.Equal(t, true, got)
+		assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() { sut.OwnsBytes(key) }))
+	})
+}
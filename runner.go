@@ -0,0 +1,176 @@
+package dbleases
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type RunnerOption func(o *RunnerOptions)
+
+type RunnerOptions struct {
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+func (opt RunnerOptions) validate() error {
+	if opt.minBackoff <= 0 || opt.maxBackoff < opt.minBackoff {
+		return fmt.Errorf("[dbleases] backoff must be positive and min not above max: %s, %s", opt.minBackoff, opt.maxBackoff)
+	}
+
+	return nil
+}
+
+func defaultRunnerOptions() RunnerOptions {
+	var opts = []RunnerOption{
+		WithBackoff(time.Second, time.Minute),
+	}
+	var o RunnerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// WithBackoff sets the wait before a worker that returned an error is restarted.
+// The wait starts at min, and doubles for each error in a row up to max.
+func WithBackoff(min, max time.Duration) RunnerOption {
+	return func(o *RunnerOptions) {
+		o.minBackoff = min
+		o.maxBackoff = max
+	}
+}
+
+// Runner runs a worker for each value of a Lease owned by the client.
+type Runner struct {
+	lease *Lease
+	fn    func(ctx context.Context, partition int) error
+	opt   RunnerOptions
+}
+
+// NewRunner of fn for each value of the Lease.
+func NewRunner(lease *Lease, fn func(ctx context.Context, partition int) error, options ...RunnerOption) (*Runner, error) {
+	o := defaultRunnerOptions()
+	for _, opt := range options {
+		opt(&o)
+	}
+
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Runner{
+		lease: lease,
+		fn:    fn,
+		opt:   o,
+	}, nil
+}
+
+// Run a worker in a goroutine for each value owned by the client, until ctx is done or the client is closed.
+//
+// The context of a worker is cancelled when the value is no longer owned by the client.
+// A worker that returns an error is restarted with a backoff. A worker that returns nil
+// is not restarted, until the value is owned by the client again.
+// Run returns when all workers have returned.
+func (r *Runner) Run(ctx context.Context) error {
+	var client = r.lease.client
+	err := client.addWorker()
+	if err != nil {
+		return err
+	}
+	defer client.workers.Done()
+
+	var (
+		wg      sync.WaitGroup
+		running = make(map[int]worker)
+		// stopped are the done channels of workers that are cancelled, but may not have returned
+		stopped = make(map[int]chan struct{})
+	)
+	defer func() {
+		for _, w := range running {
+			w.cancel()
+		}
+		wg.Wait()
+	}()
+
+	for {
+		// read changes before values, so a change in between is not missed
+		changes := r.lease.changes()
+		owned := make(map[int]bool)
+		for _, value := range r.lease.Values() {
+			owned[value] = true
+			if _, ok := running[value]; ok {
+				continue
+			}
+
+			workerCtx, cancel := context.WithCancel(ctx)
+			w := worker{cancel: cancel, done: make(chan struct{})}
+			running[value] = w
+			previous := stopped[value]
+			delete(stopped, value)
+			wg.Add(1)
+			go func(partition int) {
+				defer wg.Done()
+				defer close(w.done)
+				// a partition has at most one worker, so wait for the worker it had before
+				if previous != nil {
+					<-previous
+				}
+				r.work(workerCtx, partition)
+			}(value)
+		}
+
+		for value, w := range running {
+			if !owned[value] {
+				w.cancel()
+				stopped[value] = w.done
+				delete(running, value)
+			}
+		}
+
+		for value, done := range stopped {
+			select {
+			case <-done:
+				delete(stopped, value)
+			default:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-client.closing:
+			return nil
+		case <-changes:
+		}
+	}
+}
+
+// worker of a partition, with done closed when it has returned.
+type worker struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// work runs fn for a partition, and restarts it with a backoff on errors.
+func (r *Runner) work(ctx context.Context, partition int) {
+	var backoff = r.opt.minBackoff
+	for {
+		err := r.fn(ctx, partition)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+
+		r.lease.client.opt.logger.ErrorfContext(ctx, "[dbleases] Worker for partition %d of lease %q failed, restarting in %s: %s", partition, r.lease.name, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, r.opt.maxBackoff)
+	}
+}
@@ -0,0 +1,182 @@
+package dbleases
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyuff/dbleases/internal/assert"
+)
+
+func TestRunner(t *testing.T) {
+	var (
+		newLease = func() *Lease {
+			return &Lease{client: newTestClient("my-client", nil), name: "lease-a", changed: make(chan struct{})}
+		}
+		// tracker records the partitions with a running worker
+		newTracker = func() (*sync.Mutex, map[int]bool) {
+			return &sync.Mutex{}, make(map[int]bool)
+		}
+	)
+
+	t.Run("should run a worker for each owned value", func(t *testing.T) {
+		// arrange
+		var (
+			ctx, cancel = context.WithCancel(context.Background())
+			lease       = newLease()
+			mu, running = newTracker()
+			done        = make(chan error)
+		)
+		defer cancel()
+
+		sut, err := NewRunner(lease, func(ctx context.Context, partition int) error {
+			mu.Lock()
+			running[partition] = true
+			mu.Unlock()
+			<-ctx.Done()
+			mu.Lock()
+			delete(running, partition)
+			mu.Unlock()
+			return nil
+		})
+		assert.NoError(t, err)
+		lease.setValues(ctx, NewPartitionSet(1, 2))
+
+		// act
+		go func() { done <- sut.Run(ctx) }()
+		assert.EqualSliceWithin(t, time.Second, []bool{true, true, false}, func() []bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return []bool{running[1], running[2], running[3]}
+		})
+		lease.setValues(ctx, NewPartitionSet(2, 3))
+
+		// assert
+		assert.EqualSliceWithin(t, time.Second, []bool{false, true, true}, func() []bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return []bool{running[1], running[2], running[3]}
+		})
+		cancel()
+		assert.Equal(t, context.Canceled, <-done)
+		assert.Equal(t, 0, len(running))
+	})
+
+	t.Run("should restart a worker that fails", func(t *testing.T) {
+		// arrange
+		var (
+			ctx, cancel = context.WithCancel(context.Background())
+			lease       = newLease()
+			mu          sync.Mutex
+			calls       int
+			done        = make(chan error)
+		)
+		defer cancel()
+
+		sut, err := NewRunner(lease, func(ctx context.Context, partition int) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+			if calls < 3 {
+				return errors.New("fail")
+			}
+			return nil
+		}, WithBackoff(time.Millisecond, time.Millisecond*2))
+		assert.NoError(t, err)
+		lease.setValues(ctx, NewPartitionSet(1))
+
+		// act
+		go func() { done <- sut.Run(ctx) }()
+
+		// assert
+		assert.EqualSliceWithin(t, time.Second, []int{3}, func() []int {
+			mu.Lock()
+			defer mu.Unlock()
+			return []int{calls}
+		})
+		cancel()
+		<-done
+	})
+
+	t.Run("should stop when the client closes", func(t *testing.T) {
+		// arrange
+		var (
+			lease   = newLease()
+			done    = make(chan error)
+			started = make(chan struct{})
+		)
+
+		sut, err := NewRunner(lease, func(ctx context.Context, partition int) error {
+			close(started)
+			<-ctx.Done()
+			return nil
+		})
+		assert.NoError(t, err)
+		lease.setValues(context.Background(), NewPartitionSet(1))
+		go func() { done <- sut.Run(context.Background()) }()
+		<-started
+
+		// act
+		close(lease.client.closing)
+
+		// assert
+		assert.NoError(t, <-done)
+	})
+
+	t.Run("should wait for a revoked worker before restarting it", func(t *testing.T) {
+		// arrange
+		var (
+			ctx, cancel = context.WithCancel(context.Background())
+			lease       = newLease()
+			mu          sync.Mutex
+			workers     int
+			calls       int
+			release     = make(chan struct{})
+			revoked     = make(chan struct{})
+			done        = make(chan error)
+		)
+		defer cancel()
+
+		sut, err := NewRunner(lease, func(ctx context.Context, partition int) error {
+			mu.Lock()
+			workers++
+			calls++
+			first := calls == 1
+			mu.Unlock()
+			<-ctx.Done()
+			if first {
+				close(revoked)
+				// the first worker is slow to return after it is cancelled
+				<-release
+			}
+			mu.Lock()
+			workers--
+			mu.Unlock()
+			return nil
+		})
+		assert.NoError(t, err)
+		lease.setValues(ctx, NewPartitionSet(1))
+		go func() { done <- sut.Run(ctx) }()
+		counts := func() []int {
+			mu.Lock()
+			defer mu.Unlock()
+			return []int{workers, calls}
+		}
+		assert.EqualSliceWithin(t, time.Second, []int{1, 1}, counts)
+
+		// act
+		lease.setValues(ctx, NewPartitionSet())
+		<-revoked
+		lease.setValues(ctx, NewPartitionSet(1))
+		time.Sleep(time.Millisecond * 50)
+
+		// assert
+		assert.EqualSlice(t, []int{1, 1}, counts())
+		close(release)
+		assert.EqualSliceWithin(t, time.Second, []int{1, 2}, counts)
+		cancel()
+		<-done
+	})
+}
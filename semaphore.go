@@ -0,0 +1,59 @@
+package dbleases
+
+import "context"
+
+// semaphoreStrategy is the strategy name stored in the definition of a Semaphore.
+const semaphoreStrategy = "semaphore"
+
+// Semaphore is a distributed counting semaphore over the leases table, which is held
+// by at most its number of permits clients at a time.
+//
+// A permit is a value of the lease below the number of permits, refreshed by the
+// heartbeat of the client and evacuated when it expires, like the entries of a Lease.
+// A client holds at most one permit of a Semaphore.
+type Semaphore struct {
+	*permit
+}
+
+// Semaphore returns the named Semaphore with a number of permits.
+//
+// The number of permits is stored the first time the semaphore is used. All later
+// calls must use the same number, or ErrSizeMismatch is returned.
+func (c *Client) Semaphore(name string, permits int) (*Semaphore, error) {
+	p, err := c.permit(name, semaphoreStrategy, permits)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Semaphore{permit: p}, nil
+}
+
+// Acquire blocks until a permit is acquired, or ctx is done.
+// A permit is tried again on each heartbeat interval.
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	return s.acquire(ctx)
+}
+
+// TryAcquire a permit if one is free, and report if it was acquired.
+func (s *Semaphore) TryAcquire(ctx context.Context) (bool, error) {
+	return s.tryAcquire(ctx)
+}
+
+// Release the permit. The permit is kept if it cannot be released in the database.
+func (s *Semaphore) Release(ctx context.Context) error {
+	return s.release(ctx)
+}
+
+// Context of the permit. It is cancelled when the permit is released, or with the cause
+// ErrLockLost when it is lost. If no permit is held, the context is already cancelled.
+func (s *Semaphore) Context() context.Context {
+	return s.context()
+}
+
+// Permit is the value of the permit held by the client, from 0 up to the number of permits.
+// It is only meaningful while the Context of the permit is not done.
+func (s *Semaphore) Permit() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.value
+}
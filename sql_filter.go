@@ -0,0 +1,68 @@
+package dbleases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyuff/dbleases/internal/lease"
+)
+
+// SQLFlavour is the placeholder style of a database used by Lease.SQLFilter.
+type SQLFlavour struct {
+	placeholder func(n int) string
+}
+
+// Postgres placeholders $1, $2, ... numbered after offset existing arguments in the query.
+func Postgres(offset int) SQLFlavour {
+	return SQLFlavour{placeholder: func(n int) string {
+		return fmt.Sprintf("$%d", offset+n)
+	}}
+}
+
+// MySQL placeholders with ?.
+func MySQL() SQLFlavour {
+	return SQLFlavour{placeholder: func(int) string {
+		return "?"
+	}}
+}
+
+// SQLFilter is a predicate that matches rows where the partition column is in the values
+// of the client, as of the latest heartbeat. The column is used as is, and must be a
+// non-negative integer:
+//
+//	(MOD(column, $1) BETWEEN $2 AND $3 OR MOD(column, $4) = $5)
+//
+// Consecutive values are matched as ranges, so the predicate stays small for large leases.
+// The predicate never matches, if the client has no values.
+func (m *Lease) SQLFilter(column string, flavour SQLFlavour) (string, []any) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return sqlFilter(column, flavour, m.size, m.values)
+}
+
+func sqlFilter(column string, flavour SQLFlavour, size int, values PartitionSet) (string, []any) {
+	var spans = lease.Spans(values)
+	if len(spans) == 0 {
+		return "1 = 0", nil
+	}
+
+	var (
+		predicates []string
+		args       []any
+		bind       = func(arg any) string {
+			args = append(args, arg)
+			return flavour.placeholder(len(args))
+		}
+	)
+	for _, span := range spans {
+		mod := fmt.Sprintf("MOD(%s, %s)", column, bind(size))
+		if span.To-span.From == 1 {
+			predicates = append(predicates, fmt.Sprintf("%s = %s", mod, bind(span.From)))
+		} else {
+			predicates = append(predicates, fmt.Sprintf("%s BETWEEN %s AND %s", mod, bind(span.From), bind(span.To-1)))
+		}
+	}
+
+	return "(" + strings.Join(predicates, " OR ") + ")", args
+}
@@ -0,0 +1,51 @@
+package dbleases
+
+import (
+	"testing"
+
+	"github.com/kyuff/dbleases/internal/assert"
+)
+
+func TestSQLFilter(t *testing.T) {
+	t.Run("should match ranges with postgres placeholders", func(t *testing.T) {
+		// arrange
+		var (
+			sut = &Lease{size: 20, values: NewPartitionSet(0, 1, 2, 3, 7)}
+		)
+
+		// act
+		got, args := sut.SQLFilter("partition_col", Postgres(1))
+
+		// assert
+		assert.Equal(t, "(MOD(partition_col, $2) BETWEEN $3 AND $4 OR MOD(partition_col, $5) = $6)", got)
+		assert.EqualSlice(t, []any{20, 0, 3, 20, 7}, args)
+	})
+
+	t.Run("should match ranges with mysql placeholders", func(t *testing.T) {
+		// arrange
+		var (
+			sut = &Lease{size: 20, values: NewPartitionSet(5, 6)}
+		)
+
+		// act
+		got, args := sut.SQLFilter("partition_col", MySQL())
+
+		// assert
+		assert.Equal(t, "(MOD(partition_col, ?) BETWEEN ? AND ?)", got)
+		assert.EqualSlice(t, []any{20, 5, 6}, args)
+	})
+
+	t.Run("should match nothing without values", func(t *testing.T) {
+		// arrange
+		var (
+			sut = &Lease{size: 20}
+		)
+
+		// act
+		got, args := sut.SQLFilter("partition_col", MySQL())
+
+		// assert
+		assert.Equal(t, "1 = 0", got)
+		assert.EqualSlice(t, []any{}, args)
+	})
+}
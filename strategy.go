@@ -0,0 +1,65 @@
+package dbleases
+
+import "github.com/kyuff/dbleases/internal/lease"
+
+// Strategy assigns the values of a Lease to the clients in the ring.
+//
+// All clients of a Lease must use the same Strategy. The Strategy name is stored
+// the first time the Lease is used, and clients using another Strategy get ErrStrategyMismatch.
+type Strategy = lease.Strategy
+
+// Snapshot of the ring of a Lease, given to a Strategy on each heartbeat.
+type Snapshot = lease.Snapshot
+
+// Ring is the entries of all clients in a Lease.
+type Ring = lease.Ring
+
+// Entry in the Ring of a Lease.
+type Entry = lease.Info
+
+// Report from a Strategy with the values of the client, and the changes it must make to the Ring.
+type Report = lease.Report
+
+// Member of a Lease with the values it owns, its status and the expiry of its entries in the database.
+type Member = lease.Member
+
+// Request for an Entry in the Ring.
+type Request = lease.Request
+
+// Status of an Entry in the Ring.
+type Status = lease.Status
+
+const (
+	Pending = lease.Pending
+	Leased  = lease.Leased
+	Standby = lease.Standby
+)
+
+// Rebalancing configures how clients move values between them in the RingStrategy.
+//
+// Threshold is the difference in values between two clients before they balance.
+// MaxMoves limits the values moved by a client in each Interval, and Hold is the
+// minimum age of all entries in the ring before values are moved again.
+type Rebalancing = lease.Rebalancing
+
+// DefaultRebalancing moves half the difference between two clients at once, when it is more than 5 values.
+func DefaultRebalancing() Rebalancing {
+	return lease.DefaultRebalancing
+}
+
+// Quorum holds back the first assignment of values until enough clients are in the ring.
+type Quorum = lease.Quorum
+
+// RingStrategy is the default Strategy. Clients own the range of values from their
+// entries in the ring, and balance by requesting new entries from the largest client.
+func RingStrategy() Strategy {
+	return lease.RingStrategy{}
+}
+
+// RendezvousStrategy assigns each value to the client with the highest hash of (clientID, value).
+// When a client joins or leaves, only about 1/N of the values move between clients.
+//
+// The cost of each heartbeat grows with the size of the Lease times the number of clients.
+func RendezvousStrategy() Strategy {
+	return lease.RendezvousStrategy{}
+}
@@ -1,8 +1,11 @@
 package tests
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
 	"sync"
 	"testing"
 	"time"
@@ -62,7 +65,10 @@ func TestLeases(t *testing.T) {
 		assertValues = func(client *dbleases.Client, name string, size int, values []int) func(t *testing.T) {
 			return func(t *testing.T) {
 				t.Helper()
-				lease := client.Lease(name, size)
+				lease, err := client.Lease(name, size)
+				if !assert.NoError(t, err) {
+					return
+				}
 				if !assert.EqualSliceWithin(t, time.Second*5, values, lease.Values) {
 					t.Logf("client %q", client.ID)
 				}
@@ -71,7 +77,8 @@ func TestLeases(t *testing.T) {
 		startLease = func(client *dbleases.Client, name string, size int) func(t *testing.T) {
 			return func(t *testing.T) {
 				t.Helper()
-				client.Lease(name, size)
+				_, err := client.Lease(name, size)
+				assert.NoError(t, err)
 			}
 		}
 		closeClient = func(client *dbleases.Client) func(t *testing.T) {
@@ -92,7 +99,100 @@ func TestLeases(t *testing.T) {
 		)
 
 		// act
-		_ = client.Lease(leaseName, 100)
+		_, err := client.Lease(leaseName, 100)
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should fail on a size mismatch", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			leaseName = newLeaseName()
+			clientA   = newClient(t, newClientID())
+			clientB   = newClient(t, newClientID())
+		)
+
+		_, err := clientA.Lease(leaseName, 128)
+		assert.NoError(t, err)
+
+		// act
+		_, err = clientB.Lease(leaseName, 256)
+
+		// assert
+		assert.Equal(t, true, errors.Is(err, dbleases.ErrSizeMismatch))
+	})
+
+	t.Run("should fail on a strategy mismatch", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			leaseName = newLeaseName()
+			clientA   = newClient(t, newClientID())
+			clientB   = newClient(t, newClientID())
+		)
+
+		_, err := clientA.Lease(leaseName, 128)
+		assert.NoError(t, err)
+
+		// act
+		_, err = clientB.Lease(leaseName, 128, dbleases.WithStrategy(renamedStrategy{
+			Strategy: dbleases.RingStrategy(),
+			name:     "renamed",
+		}))
+
+		// assert
+		assert.Equal(t, true, errors.Is(err, dbleases.ErrStrategyMismatch))
+	})
+
+	t.Run("should resize a lease", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			ctx       = context.Background()
+			leaseName = newLeaseName()
+			clientA   = newClient(t, newClientID())
+			clientB   = newClient(t, newClientID())
+		)
+
+		lease, err := clientA.Lease(leaseName, 10)
+		assert.NoError(t, err)
+		assert.EqualSliceWithin(t, time.Second*2, fromTo(0, 9), lease.Values)
+
+		// act
+		err = clientA.Resize(ctx, leaseName, 20)
+
+		// assert
+		assert.NoError(t, err)
+		assert.EqualSliceWithin(t, time.Second*5, fromTo(0, 19), lease.Values)
+		assert.Equal(t, 20, lease.Size())
+		_, err = clientB.Lease(leaseName, 10)
+		assert.Equal(t, true, errors.Is(err, dbleases.ErrSizeMismatch))
+	})
+
+	t.Run("should not resize a lease with a fixed size", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			ctx          = context.Background()
+			keyspaceName = newLeaseName()
+			mutexName    = newLeaseName()
+			client       = newClient(t, newClientID())
+		)
+
+		_, err := client.KeyspaceLease(keyspaceName)
+		assert.NoError(t, err)
+		_, err = client.Mutex(mutexName)
+		assert.NoError(t, err)
+
+		// act
+		errKeyspace := client.Resize(ctx, keyspaceName, 20)
+		errMutex := client.Resize(ctx, mutexName, 2)
+
+		// assert
+		assert.Equal(t, true, errors.Is(errKeyspace, dbleases.ErrFixedSize))
+		assert.Equal(t, true, errors.Is(errMutex, dbleases.ErrFixedSize))
 	})
 
 	t.Run("should lease a full range", func(t *testing.T) {
@@ -105,10 +205,28 @@ func TestLeases(t *testing.T) {
 		)
 
 		// act
-		lease := client.Lease(leaseName, 3)
+		lease, err := client.Lease(leaseName, 3)
+
+		// assert
+		assert.NoError(t, err)
+		assert.EqualSliceWithin(t, time.Second*2, []int{0, 1, 2}, lease.Values)
+	})
+
+	t.Run("should respect max partitions", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			leaseName = newLeaseName()
+			client    = newClient(t, newClientID())
+		)
+
+		// act
+		lease, err := client.Lease(leaseName, 10, dbleases.WithMaxPartitions(3))
 
 		// assert
+		assert.NoError(t, err)
 		assert.EqualSliceWithin(t, time.Second*2, []int{0, 1, 2}, lease.Values)
+		assert.Equal(t, 7, lease.Unassigned())
 	})
 
 	t.Run("should split lease", func(t *testing.T) {
@@ -136,7 +254,9 @@ func TestLeases(t *testing.T) {
 
 		// act
 		for _, client := range clients {
-			leases = append(leases, client.Lease(leaseName, 15))
+			lease, err := client.Lease(leaseName, 15)
+			assert.NoError(t, err)
+			leases = append(leases, lease)
 		}
 
 		// assert
@@ -150,6 +270,501 @@ func TestLeases(t *testing.T) {
 		wg.Wait()
 	})
 
+	t.Run("should split lease with rendezvous hashing", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			leaseName = newLeaseName()
+			leaseSize = 50
+			clientA   = newClient(t, newClientID())
+			clientB   = newClient(t, newClientID())
+		)
+
+		// act
+		leaseA, err := clientA.Lease(leaseName, leaseSize, dbleases.WithStrategy(dbleases.RendezvousStrategy()))
+		assert.NoError(t, err)
+		leaseB, err := clientB.Lease(leaseName, leaseSize, dbleases.WithStrategy(dbleases.RendezvousStrategy()))
+		assert.NoError(t, err)
+
+		// assert
+		assert.EqualSliceWithin(t, time.Second*5, fromTo(0, leaseSize-1), func() []int {
+			values := join(leaseA.Values(), leaseB.Values())
+			sort.Ints(values)
+			return values
+		})
+	})
+
+	t.Run("should split lease with virtual nodes", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			leaseName = newLeaseName()
+			leaseSize = 100
+			clientA   = newClient(t, newClientID())
+			clientB   = newClient(t, newClientID())
+		)
+
+		// act
+		leaseA, err := clientA.Lease(leaseName, leaseSize, dbleases.WithVirtualNodes(8))
+		assert.NoError(t, err)
+		leaseB, err := clientB.Lease(leaseName, leaseSize, dbleases.WithVirtualNodes(8))
+		assert.NoError(t, err)
+
+		// assert
+		assert.EqualSliceWithin(t, time.Second*5, fromTo(0, leaseSize-1), func() []int {
+			values := join(leaseA.Values(), leaseB.Values())
+			sort.Ints(values)
+			return values
+		})
+	})
+
+	t.Run("should wait for min members", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			leaseName = newLeaseName()
+			leaseSize = 20
+			clientA   = newClient(t, "bd hash 5/20")
+			clientB   = newClient(t, "ap hash 10/20")
+		)
+
+		leaseA, err := clientA.Lease(leaseName, leaseSize, dbleases.WithMinMembers(2))
+		assert.NoError(t, err)
+		assert.EqualSliceWithin(t, time.Second, nil, leaseA.Values)
+
+		// act
+		leaseB, err := clientB.Lease(leaseName, leaseSize, dbleases.WithMinMembers(2))
+		assert.NoError(t, err)
+
+		// assert
+		assert.EqualSliceWithin(t, time.Second*2, fromTo(5, 9), leaseA.Values)
+		assert.EqualSliceWithin(t, time.Second*2, join(fromTo(0, 4), fromTo(10, 19)), leaseB.Values)
+	})
+
+	t.Run("should take over with a standby client", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			leaseName = newLeaseName()
+			leaseSize = 20
+			clientA   = newClient(t, "bd hash 5/20")
+			clientB   = newClient(t, "ap hash 10/20")
+			standby   = newClient(t, newClientID())
+		)
+
+		standbyLease, err := standby.Lease(leaseName, leaseSize, dbleases.AsStandby())
+		assert.NoError(t, err)
+
+		// act - assert
+		sequence(t,
+			startLease(clientA, leaseName, leaseSize),
+			startLease(clientB, leaseName, leaseSize),
+			parallel(
+				assertValues(clientA, leaseName, leaseSize, fromTo(5, 9)),
+				assertValues(clientB, leaseName, leaseSize, join(fromTo(0, 4), fromTo(10, 19))),
+			),
+			func(t *testing.T) {
+				assert.EqualSliceWithin(t, time.Second, nil, standbyLease.Values)
+			},
+			closeClient(clientB),
+			parallel(
+				assertValues(clientA, leaseName, leaseSize, fromTo(5, 9)),
+				func(t *testing.T) {
+					assert.EqualSliceWithin(t, time.Second*5, join(fromTo(0, 4), fromTo(10, 19)), standbyLease.Values)
+				},
+			),
+		)
+	})
+
+	t.Run("should hold replicas in another zone", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			leaseName = newLeaseName()
+			leaseSize = 20
+			clientA   = newClient(t, "bd hash 5/20", dbleases.WithZone("zone-a"))
+			clientB   = newClient(t, "ap hash 10/20", dbleases.WithZone("zone-b"))
+		)
+
+		leaseA, err := clientA.Lease(leaseName, leaseSize, dbleases.WithReplicas(1))
+		assert.NoError(t, err)
+
+		// act
+		leaseB, err := clientB.Lease(leaseName, leaseSize, dbleases.WithReplicas(1))
+		assert.NoError(t, err)
+
+		// assert
+		assert.EqualSliceWithin(t, time.Second*2, fromTo(5, 9), leaseA.Values)
+		assert.EqualSliceWithin(t, time.Second*2, join(fromTo(0, 4), fromTo(10, 19)), leaseA.Replicas)
+		assert.EqualSliceWithin(t, time.Second*2, fromTo(5, 9), leaseB.Replicas)
+	})
+
+	t.Run("should publish metadata in the ring", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			leaseName = newLeaseName()
+			clientA   = newClient(t, newClientID(), dbleases.WithMetadata(map[string]string{"hostname": "host-a"}))
+			clientB   = newClient(t, newClientID())
+			strategy  = &recordingStrategy{Strategy: dbleases.RingStrategy()}
+		)
+
+		_, err := clientA.Lease(leaseName, 10)
+		assert.NoError(t, err)
+
+		// act
+		_, err = clientB.Lease(leaseName, 10, dbleases.WithStrategy(strategy))
+		assert.NoError(t, err)
+
+		// assert
+		assert.EqualSliceWithin(t, time.Second*2, []string{"host-a"}, func() []string {
+			return strategy.metadata(clientA.ID, "hostname")
+		})
+	})
+
+	t.Run("should list the members of a lease", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			leaseName = newLeaseName()
+			leaseSize = 20
+			clientA   = newClient(t, "bd hash 5/20")
+			clientB   = newClient(t, "ap hash 10/20")
+		)
+
+		leaseA, err := clientA.Lease(leaseName, leaseSize)
+		assert.NoError(t, err)
+
+		// act
+		_, err = clientB.Lease(leaseName, leaseSize)
+		assert.NoError(t, err)
+
+		// assert
+		assert.EqualSliceWithin(t, time.Second*2, []string{
+			fmt.Sprintf("%s %v", clientB.ID, join(fromTo(0, 4), fromTo(10, 19))),
+			fmt.Sprintf("%s %v", clientA.ID, fromTo(5, 9)),
+		}, func() []string {
+			var members []string
+			for _, member := range leaseA.Members() {
+				members = append(members, fmt.Sprintf("%s %v", member.ClientID, member.Values.Values()))
+			}
+			return members
+		})
+	})
+
+	t.Run("should observe a lease without joining it", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			leaseName = newLeaseName()
+			clientA   = newClient(t, newClientID())
+		)
+
+		observer, err := dbleases.NewObserver(db, []string{leaseName},
+			dbleases.WithHeartbeat(time.Millisecond*250),
+			dbleases.WithTTL(time.Millisecond*1000),
+		)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		t.Cleanup(func() {
+			assert.NoError(t, observer.Close())
+		})
+		events := observer.Events()
+
+		// act
+		_, err = clientA.Lease(leaseName, 10)
+		assert.NoError(t, err)
+
+		// assert
+		var timeout = time.After(time.Second * 2)
+		for joined := false; !joined; {
+			select {
+			case event := <-events:
+				assert.Equal(t, leaseName, event.Name)
+				joined = len(event.Members) == 1 && event.Members[0].ClientID == clientA.ID
+			case <-timeout:
+				t.Fatal("no event from the observer")
+			}
+		}
+		assert.EqualSliceWithin(t, time.Second*2, fromTo(0, 9), func() []int {
+			members := observer.Members(leaseName)
+			if len(members) != 1 {
+				return nil
+			}
+			return members[0].Values.Values()
+		})
+	})
+
+	t.Run("should filter rows by the values of a lease", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			leaseName = newLeaseName()
+			leaseSize = 20
+			clientA   = newClient(t, "bd hash 5/20")
+			clientB   = newClient(t, "ap hash 10/20")
+			count     int
+		)
+
+		leaseA, err := clientA.Lease(leaseName, leaseSize)
+		assert.NoError(t, err)
+		_, err = clientB.Lease(leaseName, leaseSize)
+		assert.NoError(t, err)
+		assert.EqualSliceWithin(t, time.Second*2, fromTo(5, 9), leaseA.Values)
+
+		// act
+		filter, args := leaseA.SQLFilter("g.n", dbleases.Postgres(0))
+		err = db.QueryRowContext(context.Background(), "SELECT COUNT(*) FROM generate_series(0, 99) AS g(n) WHERE "+filter, args...).Scan(&count)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 25, count)
+	})
+
+	t.Run("should run a worker for each partition", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			ctx, cancel = context.WithCancel(context.Background())
+			leaseName   = newLeaseName()
+			client      = newClient(t, newClientID())
+			mu          sync.Mutex
+			started     []int
+		)
+		defer cancel()
+
+		lease, err := client.Lease(leaseName, 5)
+		assert.NoError(t, err)
+
+		runner, err := dbleases.NewRunner(lease, func(ctx context.Context, partition int) error {
+			mu.Lock()
+			started = append(started, partition)
+			mu.Unlock()
+			<-ctx.Done()
+			return nil
+		})
+		assert.NoError(t, err)
+
+		// act
+		go func() {
+			_ = runner.Run(ctx)
+		}()
+
+		// assert
+		assert.EqualSliceWithin(t, time.Second*2, fromTo(0, 4), func() []int {
+			mu.Lock()
+			defer mu.Unlock()
+			var sorted = append([]int{}, started...)
+			sort.Ints(sorted)
+			return sorted
+		})
+	})
+
+	t.Run("should cancel the context of a value that moves", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			leaseName = newLeaseName()
+			leaseSize = 20
+			clientA   = newClient(t, "bd hash 5/20")
+			clientB   = newClient(t, "ap hash 10/20")
+		)
+
+		leaseA, err := clientA.Lease(leaseName, leaseSize)
+		assert.NoError(t, err)
+		assert.EqualSliceWithin(t, time.Second*2, fromTo(0, 19), leaseA.Values)
+
+		ctx, ok := leaseA.Context(context.Background(), 0)
+		assert.Equal(t, true, ok)
+
+		// act
+		_, err = clientB.Lease(leaseName, leaseSize)
+		assert.NoError(t, err)
+
+		// assert
+		select {
+		case <-ctx.Done():
+			assert.Equal(t, true, errors.Is(context.Cause(ctx), dbleases.ErrNotOwned))
+		case <-time.After(time.Second * 2):
+			t.Fatal("context was not cancelled")
+		}
+	})
+
+	t.Run("should split the keyspace between clients", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			leaseName = newLeaseName()
+			clientA   = newClient(t, newClientID())
+			clientB   = newClient(t, newClientID())
+			key       = "some-entity-id"
+		)
+
+		keyspaceA, err := clientA.KeyspaceLease(leaseName)
+		assert.NoError(t, err)
+		assert.EqualSliceWithin(t, time.Second*2, []dbleases.KeyRange{{From: 0, To: 0}}, keyspaceA.Ranges)
+
+		// act
+		keyspaceB, err := clientB.KeyspaceLease(leaseName)
+		assert.NoError(t, err)
+
+		// assert
+		assert.EqualSliceWithin(t, time.Second*2, []bool{true}, func() []bool {
+			return []bool{keyspaceA.OwnsKey(key) != keyspaceB.OwnsKey(key) && len(keyspaceB.Ranges()) > 0}
+		})
+	})
+
+	t.Run("should balance resources between clients", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			ctx       = context.Background()
+			leaseName = newLeaseName()
+			clientA   = newClient(t, newClientID())
+			clientB   = newClient(t, newClientID())
+			keys      = []string{"tenant-a", "tenant-b", "tenant-c", "tenant-d", "tenant-e", "tenant-f"}
+		)
+
+		resourcesA, err := clientA.ResourceLease(leaseName)
+		assert.NoError(t, err)
+		resourcesB, err := clientB.ResourceLease(leaseName)
+		assert.NoError(t, err)
+		leaseA, err := clientA.Lease(leaseName, 1024)
+		assert.NoError(t, err)
+		leaseB, err := clientB.Lease(leaseName, 1024)
+		assert.NoError(t, err)
+		assert.EqualSliceWithin(t, time.Second*5, []int{1024}, func() []int {
+			a, b := leaseA.Partitions(), leaseB.Partitions()
+			if a.Len() == 0 || b.Len() == 0 {
+				return nil
+			}
+			return []int{a.Union(b).Len()}
+		})
+
+		// act
+		err = resourcesA.Add(ctx, keys...)
+		assert.NoError(t, err)
+
+		// assert
+		assert.EqualSliceWithin(t, time.Second*5, keys, func() []string {
+			var all = append(resourcesA.Keys(), resourcesB.Keys()...)
+			sort.Strings(all)
+			return all
+		})
+
+		err = resourcesB.Remove(ctx, keys...)
+		assert.NoError(t, err)
+		assert.EqualSliceWithin(t, time.Second*2, nil, resourcesA.Keys)
+	})
+
+	t.Run("should hold a mutex in one client at a time", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			ctx       = context.Background()
+			mutexName = newLeaseName()
+			clientA   = newClient(t, newClientID())
+			clientB   = newClient(t, newClientID())
+		)
+
+		mutexA, err := clientA.Mutex(mutexName)
+		assert.NoError(t, err)
+		mutexB, err := clientB.Mutex(mutexName)
+		assert.NoError(t, err)
+
+		err = mutexA.Lock(ctx)
+		assert.NoError(t, err)
+		lockedA := mutexA.Context()
+
+		// act
+		ok, err := mutexB.TryLock(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, false, ok)
+
+		err = mutexA.Unlock(ctx)
+		assert.NoError(t, err)
+		lockCtx, cancel := context.WithTimeout(ctx, time.Second*2)
+		defer cancel()
+		err = mutexB.Lock(lockCtx)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, context.Canceled, lockedA.Err())
+		assert.Equal(t, true, mutexB.Token() > mutexA.Token())
+		assert.NoError(t, mutexB.Context().Err())
+	})
+
+	t.Run("should limit the holders of a semaphore to its permits", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			ctx           = context.Background()
+			semaphoreName = newLeaseName()
+			clientA       = newClient(t, newClientID())
+			clientB       = newClient(t, newClientID())
+			clientC       = newClient(t, newClientID())
+		)
+
+		semaphoreA, err := clientA.Semaphore(semaphoreName, 2)
+		assert.NoError(t, err)
+		semaphoreB, err := clientB.Semaphore(semaphoreName, 2)
+		assert.NoError(t, err)
+		semaphoreC, err := clientC.Semaphore(semaphoreName, 2)
+		assert.NoError(t, err)
+
+		_, err = clientC.Semaphore(semaphoreName, 3)
+		assert.Equal(t, true, errors.Is(err, dbleases.ErrSizeMismatch))
+
+		// act
+		okA, err := semaphoreA.TryAcquire(ctx)
+		assert.NoError(t, err)
+		okB, err := semaphoreB.TryAcquire(ctx)
+		assert.NoError(t, err)
+		okC, err := semaphoreC.TryAcquire(ctx)
+		assert.NoError(t, err)
+
+		// assert
+		assert.EqualSlice(t, []bool{true, true, false}, []bool{okA, okB, okC})
+		assert.NotEqual(t, semaphoreA.Permit(), semaphoreB.Permit())
+
+		err = semaphoreA.Release(ctx)
+		assert.NoError(t, err)
+		acquireCtx, cancel := context.WithTimeout(ctx, time.Second*2)
+		defer cancel()
+		assert.NoError(t, semaphoreC.Acquire(acquireCtx))
+		assert.NoError(t, semaphoreC.Context().Err())
+	})
+
+	t.Run("should elect a new leader when the leader resigns", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var (
+			ctx          = context.Background()
+			electionName = newLeaseName()
+			clientA      = newClient(t, newClientID())
+			clientB      = newClient(t, newClientID())
+		)
+
+		leaderA, err := clientA.Elect(ctx, electionName)
+		assert.NoError(t, err)
+		assert.EqualSliceWithin(t, time.Second*2, []bool{true}, func() []bool { return []bool{leaderA.IsLeader()} })
+
+		leaderB, err := clientB.Elect(ctx, electionName)
+		assert.NoError(t, err)
+		defer leaderB.Resign()
+
+		// act
+		err = leaderA.Resign()
+
+		// assert
+		assert.NoError(t, err)
+		assert.EqualSliceWithin(t, time.Second*2, []bool{false, true}, func() []bool {
+			return []bool{leaderA.IsLeader(), leaderB.IsLeader()}
+		})
+		assert.Equal(t, true, leaderB.Term() > leaderA.Term())
+	})
+
 	t.Run("should ignore a hash clash", func(t *testing.T) {
 		t.Parallel()
 		// arrange
@@ -159,12 +774,14 @@ func TestLeases(t *testing.T) {
 			secondClient = newClient(t, "as hash 3/5")
 		)
 
-		firstLease := firstClient.Lease(leaseName, 5)
+		firstLease, err := firstClient.Lease(leaseName, 5)
+		assert.NoError(t, err)
 
 		// act
-		secondLease := secondClient.Lease(leaseName, 5)
+		secondLease, err := secondClient.Lease(leaseName, 5)
 
 		// assert
+		assert.NoError(t, err)
 		assert.EqualSliceWithin(t, time.Second*2, []int{0, 1, 2, 3, 4}, firstLease.Values)
 		assert.EqualSliceWithin(t, time.Second*2, []int{}, secondLease.Values)
 	})
@@ -259,6 +876,45 @@ func TestLeases(t *testing.T) {
 	})
 }
 
+type renamedStrategy struct {
+	dbleases.Strategy
+	name string
+}
+
+func (s renamedStrategy) Name() string {
+	return s.name
+}
+
+// recordingStrategy keeps the Ring of the latest Snapshot it analyzed.
+type recordingStrategy struct {
+	dbleases.Strategy
+	mu   sync.Mutex
+	ring dbleases.Ring
+}
+
+func (s *recordingStrategy) Analyze(snapshot dbleases.Snapshot) dbleases.Report {
+	s.mu.Lock()
+	s.ring = append(dbleases.Ring{}, snapshot.Ring...)
+	s.mu.Unlock()
+
+	return s.Strategy.Analyze(snapshot)
+}
+
+// metadata of the entries of a client in the recorded Ring.
+func (s *recordingStrategy) metadata(clientID, key string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var values []string
+	for _, entry := range s.ring {
+		if entry.ClientID == clientID {
+			values = append(values, entry.Metadata[key])
+		}
+	}
+
+	return values
+}
+
 func fromTo(from, to int) []int {
 	var items []int
 	for i := from; i <= to; i++ {